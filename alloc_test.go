@@ -0,0 +1,62 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// discardSpan is a recording span which discards all events.
+type discardSpan struct {
+	trace.Span
+}
+
+// IsRecording always returns true.
+func (discardSpan) IsRecording() bool { return true }
+
+// AddEvent discards the event.
+func (discardSpan) AddEvent(string, ...trace.EventOption) {}
+
+// TestAllocs locks in the maximum allocations of the hot paths.
+func TestAllocs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation tests are skipped in short mode")
+	}
+
+	fields := []zapcore.Field{
+		zap.String("str", "foo"),
+		zap.Int("int", 123),
+		zap.Bool("bool", true),
+		zap.Float64("float", 1.5),
+		zap.Duration("dur", 0),
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	allocs := testing.AllocsPerRun(100, func() {
+		attrs = AppendZapFields(attrs[:0], fields...)
+	})
+	assert.LessOrEqual(t, allocs, 1.0, "scalar fields") // duration string
+
+	span := discardSpan{Span: trace.SpanFromContext(context.Background())}
+	core := zapSpanCore{
+		level: zapcore.DebugLevel,
+		span:  span,
+		opts:  defaultOptions,
+		state: newSpanState(),
+	}
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "my message"}
+	allocs = testing.AllocsPerRun(100, func() {
+		_ = core.Write(entry, fields)
+	})
+	assert.LessOrEqual(t, allocs, 3.0, "Write with 5 fields")
+
+	allocs = testing.AllocsPerRun(100, func() {
+		_ = core.Write(entry, nil)
+	})
+	assert.Zero(t, allocs, "Write without fields")
+}
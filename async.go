@@ -0,0 +1,126 @@
+package otelzap
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// asyncDroppedCountKey is the span attribute key of the number of entries
+// dropped because the async queue was full.
+const asyncDroppedCountKey = attribute.Key("log.async.dropped_count")
+
+// WithAsync enables asynchronous mode: the entries are put to a bounded
+// queue and converted and written to the span by a background worker,
+// moving the conversion cost off latency-critical paths.
+// If the queue is full the entries are dropped, the number of dropped
+// entries is set as "log.async.dropped_count" span attribute on Sync.
+// Sync waits until all the queued entries are written.
+//
+// Note the field values are converted later, so the values
+// modified after logging (e.g. pointers) may be written modified.
+func WithAsync(queueSize int) Option {
+	return func(o *options) {
+		o.asyncQueue = queueSize
+	}
+}
+
+// asyncQueue is a queue of entries processed by a background worker.
+// The worker is started on demand and stops once the queue is empty.
+type asyncQueue struct {
+	mu      sync.Mutex
+	idle    sync.Cond // signaled when worker stops
+	items   chan bufferedEntry
+	running bool // worker is running
+	dropped int  // number of dropped entries
+}
+
+// push puts the entry to the queue, starting the worker if needed.
+// Returns false if the queue is full.
+func (q *asyncQueue) push(item bufferedEntry, size int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.items == nil {
+		q.items = make(chan bufferedEntry, size)
+		q.idle.L = &q.mu
+	}
+
+	select {
+	case q.items <- item:
+	default:
+		q.dropped++
+		return false // full
+	}
+
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+	return true
+}
+
+// run processes the queued entries until the queue is empty.
+func (q *asyncQueue) run() {
+	for {
+		items := q.drain()
+		if len(items) == 0 {
+			return // empty
+		}
+		emitAll(items)
+	}
+}
+
+// drain gets all the queued entries, the worker stops if there are none.
+func (q *asyncQueue) drain() []bufferedEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var items []bufferedEntry
+	for {
+		select {
+		case item := <-q.items:
+			items = append(items, item)
+		default:
+			if len(items) == 0 {
+				q.running = false
+				q.idle.Broadcast()
+			}
+			return items
+		}
+	}
+}
+
+// emitAll writes the queued entries converting them in bulk,
+// unless the entries might be postponed by the tail buffer.
+func emitAll(items []bufferedEntry) {
+	if items[0].core.opts.tailBuffer > 0 {
+		for _, item := range items {
+			item.core.emit(item.entry, item.fields)
+		}
+		return
+	}
+	addEvents(items, false)
+}
+
+// wait waits until all the queued entries are processed.
+func (q *asyncQueue) wait() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.running {
+		q.idle.Wait()
+	}
+}
+
+// flushDropped sets the number of dropped entries, if any, as span attribute.
+func (q *asyncQueue) flushDropped(span trace.Span) {
+	q.mu.Lock()
+	n := q.dropped
+	q.mu.Unlock()
+
+	if n != 0 {
+		span.SetAttributes(asyncDroppedCountKey.Int(n))
+	}
+}
@@ -0,0 +1,83 @@
+package otelzap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Audit field keys.
+const (
+	AuditKey         = "audit"
+	AuditActorKey    = "actor"
+	AuditResourceKey = "resource"
+)
+
+// ErrMissingAuditField is returned by Audit if
+// a mandatory field is missing or empty.
+var ErrMissingAuditField = errors.New("otelzap: missing audit field")
+
+// Audit writes an audit record of the action to both the context logger
+// (see LoggerFromContext) and the context span, tagged with `audit=true`.
+//
+// Audit records are always written: level thresholds, sampling and other
+// span logger options limiting the events are ignored. The `actor` and
+// `resource` fields are mandatory, nothing is written if any is missing.
+func Audit(ctx context.Context, action string, fields ...zap.Field) error {
+	for _, key := range []string{AuditActorKey, AuditResourceKey} {
+		if !hasAuditField(fields, key) {
+			return fmt.Errorf("%w %q of %q", ErrMissingAuditField, key, action)
+		}
+	}
+
+	fields = append(fields[:len(fields):len(fields)], zap.Bool(AuditKey, true))
+	entry := zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Time:    time.Now(),
+		Message: action,
+	}
+
+	logger := LoggerFromContext(ctx)
+	core := logger.Core()
+	o, with := defaultOptions, []zapcore.Field(nil)
+	if tee, ok := core.(spanTee); ok {
+		core = tee.core // span event is added below
+		o, with = tee.span.opts, tee.span.with
+	}
+	err := core.Write(entry, fields) // bypass level check
+
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		attrs := o.attributesFromZapFields(with, fields)
+		span.AddEvent(action,
+			trace.WithTimestamp(entry.Time),
+			trace.WithAttributes(attrs...))
+	}
+
+	return err
+}
+
+// hasAuditField checks the non-empty field is present.
+func hasAuditField(fields []zap.Field, key string) bool {
+	for _, field := range fields {
+		if field.Key != key {
+			continue
+		}
+		switch field.Type {
+		case zapcore.StringType:
+			return field.String != ""
+		case zapcore.SkipType:
+			return false
+		case zapcore.StringerType, zapcore.ReflectType,
+			zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+			return field.Interface != nil
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package otelzap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestAudit(t *testing.T) {
+	span := newRecordingSpan(t)
+	L, buf := newJSONLogger()
+	L = SpanLogger(span, L.WithOptions(zap.IncreaseLevel(zap.ErrorLevel)),
+		WithEventSampling(1, 0)).With(zap.String("foo", "bar"))
+	ctx := ContextWithLogger(context.Background(), L)
+	ctx = trace.ContextWithSpan(ctx, span)
+
+	err := Audit(ctx, "user.delete", zap.String("actor", "admin"))
+	assert.ErrorIs(t, err, ErrMissingAuditField)
+	err = Audit(ctx, "user.delete", zap.String("actor", ""), zap.String("resource", "user/1"))
+	assert.ErrorIs(t, err, ErrMissingAuditField)
+	assert.Empty(t, buf.String())
+
+	span.EXPECT().
+		AddEvent("user.delete", gomock.Any()).
+		Do(func(name string, opts ...trace.EventOption) {
+			cfg := trace.NewEventConfig(opts...)
+			assert.Equal(t, []attribute.KeyValue{
+				attribute.String("foo", "bar"),
+				attribute.String("actor", "admin"),
+				attribute.String("resource", "user/1"),
+				attribute.Bool("audit", true),
+			}, cfg.Attributes())
+		}).
+		Times(2) // not sampled
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, Audit(ctx, "user.delete",
+			zap.String("actor", "admin"),
+			zap.String("resource", "user/1")))
+	}
+	L.Info("ignored") // below threshold
+
+	assert.Equal(t, `{"level":"info","msg":"user.delete","foo":"bar","actor":"admin","resource":"user/1","audit":true}`+"\n"+
+		`{"level":"info","msg":"user.delete","foo":"bar","actor":"admin","resource":"user/1","audit":true}`, buf.Stripped())
+}
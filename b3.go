@@ -0,0 +1,61 @@
+package otelzap
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// B3 (Zipkin) header names.
+const (
+	B3Header        = "b3"
+	B3TraceIDHeader = "X-B3-TraceId"
+	B3SpanIDHeader  = "X-B3-SpanId"
+	B3SampledHeader = "X-B3-Sampled"
+)
+
+// B3Single formats the span context as B3 single header value:
+// `{TraceId}-{SpanId}-{SamplingState}`.
+// An empty string is returned for invalid span context.
+func B3Single(sc trace.SpanContext) string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID().String() + "-" + sc.SpanID().String() + "-" + b3Sampled(sc)
+}
+
+// B3Headers formats the span context as B3 multiple headers.
+// An empty header is returned for invalid span context.
+func B3Headers(sc trace.SpanContext) http.Header {
+	h := http.Header{}
+	if sc.IsValid() {
+		h.Set(B3TraceIDHeader, sc.TraceID().String())
+		h.Set(B3SpanIDHeader, sc.SpanID().String())
+		h.Set(B3SampledHeader, b3Sampled(sc))
+	}
+	return h
+}
+
+// B3Fields formats the span context as ZAP fields using
+// Zipkin naming: "traceId", "spanId" and "sampled".
+// No fields are returned for invalid span context.
+func B3Fields(sc trace.SpanContext) []zapcore.Field {
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zapcore.Field{
+		zap.String("traceId", sc.TraceID().String()),
+		zap.String("spanId", sc.SpanID().String()),
+		zap.Bool("sampled", sc.IsSampled()),
+	}
+}
+
+// b3Sampled gets B3 sampling state.
+func b3Sampled(sc trace.SpanContext) string {
+	if sc.IsSampled() {
+		return "1"
+	}
+	return "0"
+}
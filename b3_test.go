@@ -0,0 +1,42 @@
+package otelzap
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestB3 unit tests for B3 helpers.
+func TestB3(t *testing.T) {
+	assert.Empty(t, B3Single(trace.SpanContext{}))
+	assert.Empty(t, B3Headers(trace.SpanContext{}))
+	assert.Empty(t, B3Fields(trace.SpanContext{}))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	})
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10-0102030405060708-1", B3Single(sc))
+	assert.Equal(t,
+		http.Header{
+			"X-B3-Traceid": {"0102030405060708090a0b0c0d0e0f10"},
+			"X-B3-Spanid":  {"0102030405060708"},
+			"X-B3-Sampled": {"1"},
+		},
+		B3Headers(sc))
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("traceId", "0102030405060708090a0b0c0d0e0f10"),
+			zap.String("spanId", "0102030405060708"),
+			zap.Bool("sampled", true),
+		},
+		B3Fields(sc))
+
+	sc = sc.WithTraceFlags(0)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10-0102030405060708-0", B3Single(sc))
+}
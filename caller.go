@@ -0,0 +1,32 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithCallerAttributes adds "code.filepath", "code.lineno" and "code.function"
+// semantic convention attributes of the entry caller (see zap.AddCaller)
+// to every span event, so a trace event leads back to the source.
+// The file paths are trimmed by the source roots (see WithSourceRoot).
+func WithCallerAttributes() Option {
+	return func(o *options) {
+		o.callerAttrs = true
+	}
+}
+
+// appendCaller appends the caller attributes, if defined.
+func (o *options) appendCaller(attrs []attribute.KeyValue, caller zapcore.EntryCaller) []attribute.KeyValue {
+	if !caller.Defined {
+		return attrs
+	}
+
+	attrs = append(attrs,
+		semconv.CodeFilepathKey.String(o.trimSourcePath(caller.File)),
+		semconv.CodeLineNumberKey.Int(caller.Line))
+	if caller.Function != "" {
+		attrs = append(attrs, semconv.CodeFunctionKey.String(caller.Function))
+	}
+	return attrs
+}
@@ -0,0 +1,147 @@
+package otelzap
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// ConversionCache is a small LRU cache of converted field values.
+//
+// The values are identified by their pointer (pointers, maps and slices only),
+// so request-scoped constants bound with logger.With() are converted
+// (e.g. serialized to JSON) only once. The cache keeps references to the
+// cached values, so if a pointed value is modified after it was logged
+// the cached attribute becomes stale.
+//
+// The cache is safe for concurrent use and can be shared by many loggers,
+// even with different conversion options (e.g. WithFlattenStructs):
+// the values are cached per conversion. The conversions without
+// reflection (see WithNoReflection) are not cached.
+type ConversionCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[conversionKey]*list.Element
+	order list.List // most recently used first
+}
+
+// conversionKey identifies a field value and its conversion.
+type conversionKey struct {
+	flat bool // see WithFlattenStructs
+	key  string
+	typ  zapcore.FieldType
+	rt   reflect.Type
+	ptr  uintptr
+	len  int
+}
+
+// conversionItem is a cached conversion.
+type conversionItem struct {
+	key   conversionKey
+	value interface{} // keep the value alive, so its pointer is not reused
+	attrs []attribute.KeyValue
+}
+
+// NewConversionCache creates a new conversion cache
+// holding at most size converted values.
+func NewConversionCache(size int) *ConversionCache {
+	return &ConversionCache{
+		size:  size,
+		items: make(map[conversionKey]*list.Element, size),
+	}
+}
+
+// WithConversionCache enables conversion cache, so the same values
+// (e.g. structs and maps bound with logger.With()) are not converted
+// on every call. See ConversionCache for details.
+func WithConversionCache(cache *ConversionCache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// cacheKey gets the cache key of the field.
+// Returns false if field value has no identity.
+func cacheKey(field zapcore.Field, flat bool) (conversionKey, bool) {
+	switch field.Type {
+	case zapcore.ReflectType,
+		zapcore.ArrayMarshalerType,
+		zapcore.ObjectMarshalerType:
+		break // might be expensive
+	default:
+		return conversionKey{}, false
+	}
+
+	if field.Interface == nil {
+		return conversionKey{}, false
+	}
+
+	rv := reflect.ValueOf(field.Interface)
+	ck := conversionKey{flat: flat, key: field.Key, typ: field.Type, rt: rv.Type()}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if rv.IsNil() {
+			return conversionKey{}, false
+		}
+		ck.ptr = rv.Pointer()
+	case reflect.Slice:
+		if rv.Len() == 0 {
+			return conversionKey{}, false
+		}
+		ck.ptr = rv.Pointer()
+		ck.len = rv.Len()
+	default:
+		return conversionKey{}, false // no identity
+	}
+
+	return ck, true
+}
+
+// appendZapField converts and appends a ZAP field using the cache.
+// The struct values are flattened if flat is true (see WithFlattenStructs).
+func (c *ConversionCache) appendZapField(attributes []attribute.KeyValue, field zapcore.Field, flat bool) []attribute.KeyValue {
+	convert := appendZapField
+	if flat {
+		convert = appendFlattenedField
+	}
+
+	ck, ok := cacheKey(field, flat)
+	if !ok || c.size <= 0 {
+		return convert(attributes, field)
+	}
+
+	c.mu.Lock()
+	if elem, ok := c.items[ck]; ok {
+		c.order.MoveToFront(elem)
+		attrs := elem.Value.(*conversionItem).attrs
+		c.mu.Unlock()
+		return append(attributes, attrs...)
+	}
+	c.mu.Unlock()
+
+	n := len(attributes)
+	attributes = convert(attributes, field)
+	attrs := make([]attribute.KeyValue, len(attributes)-n)
+	copy(attrs, attributes[n:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[ck]; ok {
+		return attributes // already added concurrently
+	}
+	c.items[ck] = c.order.PushFront(&conversionItem{
+		key:   ck,
+		value: field.Interface,
+		attrs: attrs,
+	})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*conversionItem).key)
+	}
+
+	return attributes
+}
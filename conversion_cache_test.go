@@ -0,0 +1,96 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestConversionCache unit tests for the conversion cache.
+func TestConversionCache(t *testing.T) {
+	type Request struct {
+		ID string `json:"id"`
+	}
+
+	cache := NewConversionCache(2)
+	o := newOptions(WithConversionCache(cache))
+
+	req := &Request{ID: "foo"}
+	field := zap.Any("req", req)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("req", `{"id":"foo"}`)},
+		o.appendZapFields(nil, field))
+	assert.Equal(t, 1, cache.order.Len())
+
+	// cached, so stale on modification
+	req.ID = "bar"
+	assert.Equal(t, []attribute.KeyValue{attribute.String("req", `{"id":"foo"}`)},
+		o.appendZapFields(nil, field))
+	assert.Equal(t, 1, cache.order.Len())
+
+	// another key is another item
+	assert.Equal(t, []attribute.KeyValue{attribute.String("req2", `{"id":"bar"}`)},
+		o.appendZapFields(nil, zap.Any("req2", req)))
+	assert.Equal(t, 2, cache.order.Len())
+
+	// the least recently used is evicted
+	m := map[string]int{"a": 1}
+	assert.Equal(t, []attribute.KeyValue{attribute.String("m", `{"a":1}`)},
+		o.appendZapFields(nil, zap.Any("m", m)))
+	assert.Equal(t, 2, cache.order.Len())
+	assert.Equal(t, []attribute.KeyValue{attribute.String("req", `{"id":"bar"}`)},
+		o.appendZapFields(nil, field))
+
+	// no identity, not cached
+	assert.Equal(t, []attribute.KeyValue{attribute.String("val", `{"id":"baz"}`)},
+		o.appendZapFields(nil, zap.Any("val", Request{ID: "baz"})))
+	assert.Equal(t, []attribute.KeyValue{attribute.Int("int", 1)},
+		o.appendZapFields(nil, zap.Int("int", 1)))
+	assert.Equal(t, 2, cache.order.Len())
+}
+
+// TestConversionCacheShared unit tests for the cache shared
+// by loggers with different conversion options.
+func TestConversionCacheShared(t *testing.T) {
+	type Request struct {
+		ID string `json:"id"`
+	}
+
+	cache := NewConversionCache(4)
+	plain := newOptions(WithConversionCache(cache))
+	flat := newOptions(WithConversionCache(cache), WithFlattenStructs())
+
+	field := zap.Any("req", &Request{ID: "foo"})
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, []attribute.KeyValue{attribute.String("req", `{"id":"foo"}`)},
+			plain.appendZapFields(nil, field))
+		assert.Equal(t, []attribute.KeyValue{attribute.String("req.id", "foo")},
+			flat.appendZapFields(nil, field))
+	}
+	assert.Equal(t, 2, cache.order.Len())
+}
+
+// TestCacheKey unit tests for the conversion cache key.
+func TestCacheKey(t *testing.T) {
+	var nilMap map[string]int
+	for _, field := range []zapcore.Field{
+		zap.String("str", "foo"),
+		zap.Any("nil", nilMap),
+		zap.Strings("empty", nil),
+		zap.Reflect("value", struct{}{}),
+	} {
+		_, ok := cacheKey(field, false)
+		assert.False(t, ok, field.Key)
+	}
+
+	strs := []string{"foo", "bar"}
+	k1, ok := cacheKey(zap.Strings("strs", strs), false)
+	if assert.True(t, ok) {
+		k2, _ := cacheKey(zap.Strings("strs", strs[:1]), false)
+		assert.NotEqual(t, k1, k2) // different length
+		k3, _ := cacheKey(zap.Strings("strs", strs), true)
+		assert.NotEqual(t, k1, k3) // different conversion
+	}
+}
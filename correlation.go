@@ -0,0 +1,48 @@
+package otelzap
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// CorrelationIDKey is the log field and attribute key of correlation ID.
+const CorrelationIDKey = "correlation.id"
+
+// DefaultRequestIDHeader is the HTTP header used to pass generated request IDs
+// if no correlation header is configured.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// CorrelationID reads the correlation (request) ID from the carrier's header
+// and returns it as both ZAP field and OpenTelemetry attribute.
+// If there is no such header then `ok` is false.
+func CorrelationID(carrier propagation.TextMapCarrier, header string) (field zapcore.Field, attr attribute.KeyValue, ok bool) {
+	id := carrier.Get(header)
+	if id == "" {
+		return zap.Skip(), attribute.KeyValue{}, false
+	}
+
+	return zap.String(CorrelationIDKey, id), attribute.String(CorrelationIDKey, id), true
+}
+
+// HTTPCorrelationID similar to CorrelationID but reads the HTTP header.
+func HTTPCorrelationID(h http.Header, header string) (field zapcore.Field, attr attribute.KeyValue, ok bool) {
+	return CorrelationID(propagation.HeaderCarrier(h), header)
+}
+
+// NewRequestID generates a new random request ID (UUID version 4).
+func NewRequestID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil { // unlikely
+		handleError(err)
+	}
+	id[6] = (id[6] & 0x0f) | 0x40 // version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
@@ -0,0 +1,37 @@
+package otelzap
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+// TestCorrelationID unit tests for CorrelationID function.
+func TestCorrelationID(t *testing.T) {
+	h := http.Header{}
+	field, attr, ok := HTTPCorrelationID(h, "X-Request-ID")
+	assert.False(t, ok)
+	assert.Equal(t, zap.Skip(), field)
+	assert.Equal(t, attribute.KeyValue{}, attr)
+
+	h.Set("X-Request-ID", "123")
+	field, attr, ok = HTTPCorrelationID(h, "x-request-id")
+	assert.True(t, ok)
+	assert.Equal(t, zap.String("correlation.id", "123"), field)
+	assert.Equal(t, attribute.String("correlation.id", "123"), attr)
+
+	_, attr, ok = CorrelationID(propagation.MapCarrier{"x-correlation-id": "456"}, "x-correlation-id")
+	assert.True(t, ok)
+	assert.Equal(t, attribute.String("correlation.id", "456"), attr)
+}
+
+// TestNewRequestID unit tests for NewRequestID function.
+func TestNewRequestID(t *testing.T) {
+	id1, id2 := NewRequestID(), NewRequestID()
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id1)
+	assert.NotEqual(t, id1, id2)
+}
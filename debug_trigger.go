@@ -0,0 +1,43 @@
+package otelzap
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithDebugTrigger raises the span events level to Debug whenever
+// the trace state of the span or the context baggage (see SpanLoggerFromContext
+// and Middleware) has the member with the value (e.g. "debug=1"),
+// enabling per-request verbose tracing triggered by a request header.
+// The log output level is not changed. The member must be present,
+// so the empty key or value disables the trigger (reported as an error).
+func WithDebugTrigger(key, value string) Option {
+	if key == "" || value == "" {
+		handleError(fmt.Errorf("otelzap: empty debug trigger %q=%q", key, value))
+		return func(*options) {}
+	}
+
+	return func(o *options) {
+		o.debugKey = key
+		o.debugValue = value
+	}
+}
+
+// debugTriggered checks if the span trace state has the debug trigger.
+func (o *options) debugTriggered(span trace.Span) bool {
+	return o.debugKey != "" &&
+		span.SpanContext().TraceState().Get(o.debugKey) == o.debugValue
+}
+
+// debugOptions adds the Debug span level if the context baggage
+// has the debug trigger.
+func (o *options) debugOptions(ctx context.Context, opts []Option) []Option {
+	if o.debugKey == "" || baggage.FromContext(ctx).Member(o.debugKey).Value() != o.debugValue {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], WithSpanLevel(zapcore.DebugLevel))
+}
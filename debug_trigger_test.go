@@ -0,0 +1,26 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestDebugTriggerEmpty unit tests for the empty debug trigger.
+func TestDebugTriggerEmpty(t *testing.T) {
+	var errs []error
+	defer func(h func(error)) { handleError = h }(handleError)
+	handleError = func(err error) { errs = append(errs, err) }
+
+	o := newOptions(WithDebugTrigger("debug", ""))
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], `otelzap: empty debug trigger "debug"=""`)
+	}
+
+	// no member, not triggered
+	span := trace.SpanFromContext(context.Background())
+	assert.False(t, o.debugTriggered(span))
+	assert.Empty(t, o.debugOptions(context.Background(), nil))
+}
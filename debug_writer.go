@@ -0,0 +1,55 @@
+package otelzap
+
+import (
+	"io"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// debugWriter prints span events in human-readable form.
+type debugWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithDebugWriter also prints each span event (name and attributes)
+// to the writer (e.g. os.Stdout), so it is easy to see what exactly
+// is attached to spans during development.
+func WithDebugWriter(w io.Writer) Option {
+	return func(o *options) {
+		if w == nil {
+			o.debug = nil
+			return
+		}
+		o.debug = &debugWriter{w: w}
+	}
+}
+
+// write prints a span event with aligned attributes (see Sprint), e.g.:
+//
+//	span event "my message":
+//	  zap.level = "info"
+//	  foo       = 123
+func (dw *debugWriter) write(name string, attrs []attribute.KeyValue) {
+	pbuf := bufferPool.Get().(*[]byte)
+	buf := append((*pbuf)[:0], "span event "...)
+	buf = strconv.AppendQuote(buf, name)
+	buf = append(buf, ':')
+	if len(attrs) != 0 {
+		buf = append(buf, '\n')
+		buf = appendPretty(buf, attrs, "  ")
+	}
+	buf = append(buf, '\n')
+
+	dw.mu.Lock()
+	_, err := dw.w.Write(buf)
+	dw.mu.Unlock()
+	if err != nil {
+		handleError(err)
+	}
+
+	*pbuf = buf[:0]
+	bufferPool.Put(pbuf)
+}
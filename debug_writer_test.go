@@ -0,0 +1,30 @@
+package otelzap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestDebugWriter unit tests for the debug writer.
+func TestDebugWriter(t *testing.T) {
+	var buf bytes.Buffer
+	o := newOptions(WithDebugWriter(&buf))
+
+	o.debug.write("my message", []attribute.KeyValue{
+		attribute.String("zap.level", "info"),
+		attribute.Int("foo", 123),
+		attribute.StringSlice("bar", []string{"a", "b"}),
+	})
+	o.debug.write("no attributes", nil)
+
+	assert.Equal(t, `span event "my message":`+"\n"+
+		`  zap.level = "info"`+"\n"+
+		`  foo       = 123`+"\n"+
+		`  bar       = [a b]`+"\n"+
+		`span event "no attributes":`+"\n", buf.String())
+
+	assert.Nil(t, newOptions(WithDebugWriter(&buf), WithDebugWriter(nil)).debug)
+}
@@ -0,0 +1,93 @@
+package otelzap
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// repeatCountKey is the attribute key of the number of collapsed events.
+const repeatCountKey = attribute.Key("log.repeat_count")
+
+// dedupEvent is the last written event.
+type dedupEvent struct {
+	message string
+	level   zapcore.Level
+	attrs   []attribute.KeyValue
+	time    time.Time // time of the last repeat
+	count   int       // number of collapsed repeats
+}
+
+// WithDedupWindow collapses identical (message, level and attributes)
+// events repeated within the window: the first event is written as usual,
+// the repeats are counted and written as a single copy of the event with
+// the "log.repeat_count" attribute once the series ends (or on Sync).
+// This prevents retry storms from flooding spans.
+func WithDedupWindow(window time.Duration) Option {
+	return func(o *options) {
+		o.dedupWindow = window
+	}
+}
+
+// dedup checks if the event repeats the last one within the window.
+// Returns false if the event should be suppressed.
+// The previous series of repeats, if any, is returned to be written.
+func (st *spanState) dedup(entry zapcore.Entry, attrs []attribute.KeyValue, window time.Duration) (dedupEvent, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	last := st.last
+	if last.message == entry.Message && last.level == entry.Level &&
+		entry.Time.Sub(last.time) <= window && equalAttributes(last.attrs, attrs) {
+		st.last.count++
+		st.last.time = entry.Time
+		return dedupEvent{}, false // repeated
+	}
+
+	st.last = dedupEvent{
+		message: entry.Message,
+		level:   entry.Level,
+		attrs:   attrs,
+		time:    entry.Time,
+	}
+	return last, true
+}
+
+// flushDedup writes the pending series of repeats.
+func (zs zapSpanCore) flushDedup() {
+	st := zs.state
+	st.mu.Lock()
+	last := st.last
+	st.last = dedupEvent{}
+	st.mu.Unlock()
+
+	zs.writeRepeats(last)
+}
+
+// writeRepeats writes the collapsed repeats, if any, as a single event.
+func (zs zapSpanCore) writeRepeats(de dedupEvent) {
+	if de.count == 0 {
+		return // no repeats
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(de.attrs)+1)
+	attrs = append(attrs, de.attrs...)
+	attrs = append(attrs, repeatCountKey.Int(de.count))
+	zs.addEventAs(de.message, attrs, nil,
+		[]trace.EventOption{trace.WithTimestamp(de.time)})
+}
+
+// equalAttributes checks if two lists of attributes are the same.
+func equalAttributes(a, b []attribute.KeyValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package otelzap
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// Development is a ready-made development setup: a console logger
+// and a tracer printing finished spans (including log events) to stdout.
+type Development struct {
+	Logger *zap.Logger  // console logger
+	Tracer trace.Tracer // stdout tracer
+
+	provider *sdktrace.TracerProvider
+	opts     []Option
+}
+
+// NewDevelopment creates a development setup, so it is easy
+// to see the integration working in one call:
+//
+//	dev, err := otelzap.NewDevelopment()
+//	...
+//	defer dev.Shutdown(context.Background())
+//	ctx, span, logger := dev.Start(ctx, "my-span")
+//	defer span.End()
+//	logger.Info("hello")
+func NewDevelopment(opts ...Option) (*Development, error) {
+	return newDevelopment(os.Stdout, opts...)
+}
+
+// newDevelopment creates a development setup printing spans to the writer.
+func newDevelopment(w io.Writer, opts ...Option) (*Development, error) {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := stdouttrace.New(
+		stdouttrace.WithWriter(w),
+		stdouttrace.WithPrettyPrint())
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter))
+	return &Development{
+		Logger:   logger,
+		Tracer:   provider.Tracer("github.com/Pilatuz/otelzap"),
+		provider: provider,
+		opts:     opts,
+	}, nil
+}
+
+// Start starts a new span and creates the logger writing to it.
+func (d *Development) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span, *zap.Logger) {
+	ctx, span := d.Tracer.Start(ctx, name, opts...)
+	return ctx, span, SpanLogger(span, d.Logger, d.opts...)
+}
+
+// Shutdown flushes the logger and shuts the tracer down.
+func (d *Development) Shutdown(ctx context.Context) error {
+	return multierr.Append(
+		d.provider.Shutdown(ctx),
+		d.Logger.Sync())
+}
@@ -0,0 +1,30 @@
+package otelzap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestDevelopment unit tests for the development setup.
+func TestDevelopment(t *testing.T) {
+	var buf bytes.Buffer
+	dev, err := newDevelopment(&buf)
+	require.NoError(t, err)
+
+	dev.Logger = zaptest.NewLogger(t) // stderr cannot be synced
+
+	_, span, logger := dev.Start(context.Background(), "my-span")
+	logger.Info("my message", zap.Int("foo", 123))
+	span.End()
+	assert.NoError(t, dev.Shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `"Name": "my-span"`)
+	assert.Contains(t, buf.String(), `"Name": "my message"`)
+	assert.Contains(t, buf.String(), `"Key": "foo"`)
+}
@@ -0,0 +1,43 @@
+package otelzap
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DurationMillis creates a field with the duration in milliseconds.
+// Unlike zap.Duration the converted attribute is numeric (float64),
+// so it can be aggregated, e.g. as a histogram.
+func DurationMillis(key string, d time.Duration) zapcore.Field {
+	return zap.Float64(key, durationIn(d, time.Millisecond))
+}
+
+// DurationSeconds creates a field with the duration in seconds.
+// Unlike zap.Duration the converted attribute is numeric (float64),
+// so it can be aggregated, e.g. as a histogram.
+func DurationSeconds(key string, d time.Duration) zapcore.Field {
+	return zap.Float64(key, durationIn(d, time.Second))
+}
+
+// WithNumericDurations converts zap.Duration fields to numeric (float64)
+// attributes in the unit (e.g. time.Millisecond) instead of strings like "1.5s".
+// Zero or negative unit disables the conversion.
+func WithNumericDurations(unit time.Duration) Option {
+	return func(o *options) {
+		o.durationUnit = unit
+	}
+}
+
+// appendNumericDuration appends the numeric duration attribute.
+func (o *options) appendNumericDuration(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	d := time.Duration(field.Integer)
+	return append(attributes, attribute.Float64(field.Key, durationIn(d, o.durationUnit)))
+}
+
+// durationIn gets the duration in the unit.
+func durationIn(d, unit time.Duration) float64 {
+	return float64(d) / float64(unit)
+}
@@ -0,0 +1,39 @@
+package otelzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestDurationFields unit tests for numeric duration fields.
+func TestDurationFields(t *testing.T) {
+	d := 1500 * time.Millisecond
+	assert.Equal(t, zap.Float64("elapsed", 1500), DurationMillis("elapsed", d))
+	assert.Equal(t, zap.Float64("elapsed", 1.5), DurationSeconds("elapsed", d))
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.Float64("elapsed", 1.5)},
+		AppendZapFields(nil, DurationSeconds("elapsed", d)))
+}
+
+// TestNumericDurations unit tests for WithNumericDurations option.
+func TestNumericDurations(t *testing.T) {
+	d := 1500 * time.Millisecond
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.String("elapsed", "1.5s")},
+		newOptions().appendZapFields(nil, zap.Duration("elapsed", d)))
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.Float64("elapsed", 1500)},
+		newOptions(WithNumericDurations(time.Millisecond)).appendZapFields(nil, zap.Duration("elapsed", d)))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Float64("elapsed", 1.5),
+			attribute.String("elapsed.unit", "s"),
+		},
+		newOptions(WithNumericDurations(time.Second), WithNoReflection(),
+			WithUnits(map[string]string{"elapsed": "s"})).
+			appendZapFields(nil, zap.Duration("elapsed", d)))
+}
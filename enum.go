@@ -0,0 +1,52 @@
+package otelzap
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OtherValue replaces unexpected values of the enum-like attributes.
+const OtherValue = "other"
+
+// rawSuffix is the key suffix of the unexpected enum values.
+const rawSuffix = ".raw"
+
+// WithEnum registers the allowed values of the string attribute.
+// The values are matched case-insensitively and normalized
+// to the registered form. Unexpected values are replaced by "other"
+// and the raw value is added as "<key>.raw" attribute, keeping
+// attribute cardinality predictable for metrics derived from events.
+// Can be used multiple times for different keys.
+func WithEnum(key string, values ...string) Option {
+	return func(o *options) {
+		if o.enums == nil {
+			o.enums = make(map[string]map[string]string)
+		}
+		allowed := make(map[string]string, len(values))
+		for _, v := range values {
+			allowed[strings.ToLower(v)] = v
+		}
+		o.enums[key] = allowed
+	}
+}
+
+// normalizeEnums normalizes the values of the converted enum-like attributes.
+func (o *options) normalizeEnums(attributes []attribute.KeyValue, from int) []attribute.KeyValue {
+	for i, n := from, len(attributes); i < n; i++ {
+		kv := attributes[i]
+		allowed, ok := o.enums[string(kv.Key)]
+		if !ok || kv.Value.Type() != attribute.STRING {
+			continue
+		}
+
+		raw := kv.Value.AsString()
+		if v, ok := allowed[strings.ToLower(raw)]; ok {
+			attributes[i].Value = attribute.StringValue(v)
+			continue
+		}
+		attributes[i].Value = attribute.StringValue(OtherValue)
+		attributes = append(attributes, attribute.String(string(kv.Key)+rawSuffix, raw))
+	}
+	return attributes
+}
@@ -0,0 +1,31 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestEnum unit tests for WithEnum option.
+func TestEnum(t *testing.T) {
+	o := newOptions(
+		WithEnum("method", "GET", "POST"),
+		WithEnum("result", "ok", "failed"))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("method", "GET"),
+			attribute.String("result", OtherValue),
+			attribute.String("result.raw", "timeout"),
+			attribute.String("method", "POST"),
+			attribute.Int("result", 1), // not a string
+			attribute.String("foo", "bar"),
+		},
+		o.appendZapFields(nil,
+			zap.String("method", "get"),
+			zap.String("result", "timeout"),
+			zap.String("method", "Post"),
+			zap.Int("result", 1),
+			zap.String("foo", "bar")))
+}
@@ -0,0 +1,9 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel"
+)
+
+// handleError reports internal errors, by default
+// to the OpenTelemetry global error handler.
+var handleError = otel.Handle
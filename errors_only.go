@@ -0,0 +1,116 @@
+package otelzap
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SpanErrorsOnly creates ZAP logger which writes only Error+ entries
+// to the span: the error is recorded (see trace.Span.RecordError)
+// and the span status is set to error with the entry message.
+// All other entries pass straight through with no overhead.
+//
+// The error is taken from the last zap.Error() field, if any,
+// otherwise it is created from the entry message.
+func SpanErrorsOnly(span trace.Span, logger *zap.Logger, opts ...Option) *zap.Logger {
+	if span == nil || !span.IsRecording() {
+		return logger // no tracing enabled
+	}
+
+	o := newOptions(opts...)
+	wrap := func(core zapcore.Core) zapcore.Core {
+		return errorsOnlyCore{core: core, span: span, opts: o}
+	}
+
+	return logger.WithOptions(zap.WrapCore(wrap))
+}
+
+// errorsOnlyCore records Error+ entries on the span.
+type errorsOnlyCore struct {
+	core zapcore.Core // the original core
+	span trace.Span
+	with []zapcore.Field
+	opts *options
+}
+
+// Enabled checks if logging level is enabled.
+func (c errorsOnlyCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel || c.core.Enabled(level)
+}
+
+// With adds structured context to the Core.
+func (c errorsOnlyCore) With(fields []zapcore.Field) zapcore.Core {
+	return errorsOnlyCore{
+		core: c.core.With(fields),
+		span: c.span,
+		with: concatFields(c.with, fields),
+		opts: c.opts,
+	}
+}
+
+// Check determines whether the supplied Entry should be logged.
+func (c errorsOnlyCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked = c.core.Check(entry, checked)
+	if entry.Level < zapcore.ErrorLevel {
+		return checked // pass through
+	}
+
+	return checked.AddCore(entry, errorsOnlySpanCore{c})
+}
+
+// Write writes the Entry to the original core.
+func (c errorsOnlyCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.core.Write(entry, fields)
+}
+
+// Sync flushes the original core.
+func (c errorsOnlyCore) Sync() error {
+	return c.core.Sync()
+}
+
+// errorsOnlySpanCore records the entries on the span only.
+type errorsOnlySpanCore struct {
+	errorsOnlyCore
+}
+
+// Write records the error and sets the span status.
+func (c errorsOnlySpanCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	err := lastError(fields)
+	if err == nil {
+		err = lastError(c.with)
+	}
+
+	var extra []attribute.KeyValue
+	if err == nil {
+		err = errors.New(entry.Message)
+	} else {
+		extra = append(extra, messageKey.String(entry.Message))
+	}
+
+	attrs := c.opts.attributesFromZapFields(c.with, fields, extra...)
+	c.span.RecordError(err, trace.WithAttributes(attrs...))
+	c.span.SetStatus(codes.Error, entry.Message)
+	return nil
+}
+
+// Sync does nothing, the span is written immediately.
+func (c errorsOnlySpanCore) Sync() error {
+	return nil
+}
+
+// lastError gets the error of the last zap.Error() field, if any.
+func lastError(fields []zapcore.Field) error {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Type == zapcore.ErrorType {
+			if err, ok := fields[i].Interface.(error); ok && err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package otelzap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestSpanErrorsOnly(t *testing.T) {
+	L1 := zap.NewNop()
+	assert.Same(t, L1, SpanErrorsOnly(nil, L1))
+
+	span := newRecordingSpan(t)
+	L, buf := newJSONLogger()
+	SL := SpanErrorsOnly(span, L).With(zap.String("foo", "bar"))
+
+	SL.Info("no span activity")
+	SL.Warn("no span activity")
+
+	span.EXPECT().
+		RecordError(assert.AnError,
+			trace.WithAttributes(
+				attribute.String("log.message", "failed"),
+				attribute.String("foo", "bar"),
+				attribute.String("error", assert.AnError.Error()),
+			))
+	span.EXPECT().SetStatus(codes.Error, "failed")
+	SL.Error("failed", zap.Error(assert.AnError))
+
+	span.EXPECT().
+		RecordError(errorMessage("no error field"),
+			trace.WithAttributes(
+				attribute.String("foo", "bar"),
+			))
+	span.EXPECT().SetStatus(codes.Error, "no error field")
+	SL.Error("no error field")
+
+	assert.Equal(t, `{"level":"info","msg":"no span activity","foo":"bar"}`+"\n"+
+		`{"level":"warn","msg":"no span activity","foo":"bar"}`+"\n"+
+		`{"level":"error","msg":"failed","foo":"bar","error":"assert.AnError general error for testing"}`+"\n"+
+		`{"level":"error","msg":"no error field","foo":"bar"}`, buf.Stripped())
+}
+
+// errorMessage matches an error by its message.
+type errorMessage string
+
+// Matches checks the error message.
+func (m errorMessage) Matches(x interface{}) bool {
+	err, ok := x.(error)
+	return ok && err.Error() == string(m)
+}
+
+// String describes the matcher.
+func (m errorMessage) String() string {
+	return "error " + string(m)
+}
@@ -0,0 +1,210 @@
+package otelzap
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OverflowPolicy defines how to handle events exceeding the byte budget.
+type OverflowPolicy int
+
+// Known overflow policies.
+const (
+	// OverflowDropLargest drops the largest attributes first.
+	OverflowDropLargest OverflowPolicy = iota
+
+	// OverflowTruncate truncates all string values proportionally.
+	OverflowTruncate
+
+	// OverflowJSON packs all attributes into a single JSON attribute.
+	// If still too large, the trailing fields of the JSON object are dropped,
+	// so the value is always a valid JSON, and "otelzap.truncated" is set.
+	OverflowJSON
+)
+
+// attributeSize gets the size of attribute: key and value.
+func attributeSize(attr attribute.KeyValue) int {
+	if attr.Value.Type() == attribute.STRING {
+		return len(attr.Key) + len(attr.Value.AsString())
+	}
+	return len(attr.Key) + len(attr.Value.Emit())
+}
+
+// attributesSize gets the total size of attributes.
+func attributesSize(attrs []attribute.KeyValue) int {
+	total := 0
+	for _, attr := range attrs {
+		total += attributeSize(attr)
+	}
+	return total
+}
+
+// applyBudget fits attributes into the byte budget using the overflow policy.
+func applyBudget(attrs []attribute.KeyValue, budget int, policy OverflowPolicy) []attribute.KeyValue {
+	if budget < 0 {
+		budget = 0
+	}
+	if attributesSize(attrs) <= budget {
+		return attrs // fit, nothing to do
+	}
+
+	switch policy {
+	case OverflowTruncate:
+		attrs = truncateAttributes(attrs, budget)
+		if attributesSize(attrs) > budget {
+			// non-string values are still too large
+			return dropLargest(attrs, budget)
+		}
+		return attrs
+
+	case OverflowJSON:
+		obj := attrs[0]
+		if len(attrs) != 1 || obj.Key != jsonFieldsKey {
+			obj = jsonObject(jsonFieldsKey, attrs)
+		}
+		return truncateJSON(obj, budget)
+	}
+
+	return dropLargest(attrs, budget)
+}
+
+// truncatedKey is the attribute key to mark truncated events.
+const truncatedKey = "otelzap.truncated"
+
+// truncateJSON drops the trailing fields of JSON object until it fits
+// into the budget. The truncated flag is added if any field is dropped.
+func truncateJSON(obj attribute.KeyValue, budget int) []attribute.KeyValue {
+	members, ok := jsonMembers(obj.Value.AsString())
+	size := 2 // {}
+	for i, m := range members {
+		if i > 0 {
+			size++ // comma
+		}
+		size += len(m)
+	}
+
+	flag := attribute.Bool(truncatedKey, true)
+	keep := len(members)
+	if budget -= len(obj.Key); size > budget {
+		budget -= attributeSize(flag)
+		for keep > 0 && size > budget {
+			keep--
+			size -= len(members[keep])
+			if keep > 0 {
+				size-- // comma
+			}
+		}
+	}
+
+	value := attribute.String(string(obj.Key), "{"+strings.Join(members[:keep], ",")+"}")
+	if !ok || keep < len(members) {
+		return []attribute.KeyValue{value, flag}
+	}
+	return []attribute.KeyValue{value}
+}
+
+// jsonMembers splits JSON object into `"key":value` members
+// preserving their order.
+func jsonMembers(s string) ([]string, bool) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, false // not an object
+	}
+
+	var members []string
+	for dec.More() {
+		t, err := dec.Token()
+		key, ok := t.(string)
+		if err != nil || !ok {
+			return nil, false
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		k, _ := json.Marshal(key) // never fails
+		members = append(members, string(k)+":"+string(value))
+	}
+
+	return members, true
+}
+
+// dropLargest drops the largest attributes until they fit into the budget.
+// The order of remaining attributes is preserved.
+func dropLargest(attrs []attribute.KeyValue, budget int) []attribute.KeyValue {
+	index := make([]int, len(attrs))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		return attributeSize(attrs[index[i]]) > attributeSize(attrs[index[j]])
+	})
+
+	drop := make(map[int]bool, len(attrs))
+	total := attributesSize(attrs)
+	for _, i := range index {
+		if total <= budget {
+			break
+		}
+		total -= attributeSize(attrs[i])
+		drop[i] = true
+	}
+
+	out := make([]attribute.KeyValue, 0, len(attrs)-len(drop))
+	for i, attr := range attrs {
+		if !drop[i] {
+			out = append(out, attr)
+		}
+	}
+	return out
+}
+
+// truncateAttributes truncates string values proportionally to fit the budget.
+func truncateAttributes(attrs []attribute.KeyValue, budget int) []attribute.KeyValue {
+	fixed, strs := 0, 0
+	for _, attr := range attrs {
+		if attr.Value.Type() == attribute.STRING {
+			fixed += len(attr.Key)
+			strs += len(attr.Value.AsString())
+		} else {
+			fixed += attributeSize(attr)
+		}
+	}
+	if strs == 0 {
+		return attrs // nothing to truncate
+	}
+
+	avail := budget - fixed
+	if avail < 0 {
+		avail = 0
+	}
+
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr.Value.Type() == attribute.STRING {
+			s := attr.Value.AsString()
+			attr = attribute.String(string(attr.Key), truncateString(s, len(s)*avail/strs))
+		}
+		out = append(out, attr)
+	}
+	return out
+}
+
+// truncateString truncates string to at most n bytes
+// without breaking multi-byte characters.
+func truncateString(s string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,82 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestApplyBudget unit tests for applyBudget function.
+func TestApplyBudget(t *testing.T) {
+	attrs := []attribute.KeyValue{
+		attribute.String("a", "1234567890"), // 11
+		attribute.Int("b", 123),             // 4
+		attribute.String("c", "12345"),      // 6
+	}
+	assert.Equal(t, 21, attributesSize(attrs))
+
+	// fit
+	assert.Equal(t, attrs, applyBudget(attrs, 21, OverflowDropLargest))
+
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Int("b", 123),
+			attribute.String("c", "12345"),
+		},
+		applyBudget(attrs, 15, OverflowDropLargest))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Int("b", 123),
+		},
+		applyBudget(attrs, 9, OverflowDropLargest))
+	assert.Empty(t, applyBudget(attrs, -1, OverflowDropLargest))
+
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("a", "123456"),
+			attribute.Int("b", 123),
+			attribute.String("c", "123"),
+		},
+		applyBudget(attrs, 15, OverflowTruncate))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("a", ""),
+			attribute.String("c", ""),
+		},
+		applyBudget(attrs, 5, OverflowTruncate)) // non-strings dropped
+
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("log.fields", `{}`),
+			attribute.Bool("otelzap.truncated", true),
+		},
+		applyBudget(attrs, 20, OverflowJSON))
+
+	// trailing fields are dropped
+	packed := []attribute.KeyValue{
+		attribute.String("log.fields", `{"a":"1234567890","b":123,"c":"12345678901234567890"}`),
+	}
+	assert.Equal(t, packed, applyBudget(packed, 63, OverflowJSON))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("log.fields", `{"a":"1234567890"}`),
+			attribute.Bool("otelzap.truncated", true),
+		},
+		applyBudget(packed, 50, OverflowJSON))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("log.fields", `{"a":"1234567890","b":123}`),
+			attribute.Bool("otelzap.truncated", true),
+		},
+		applyBudget(packed, 57, OverflowJSON))
+}
+
+// TestTruncateString unit tests for truncateString function.
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "", truncateString("hello", -1))
+	assert.Equal(t, "", truncateString("hello", 0))
+	assert.Equal(t, "hel", truncateString("hello", 3))
+	assert.Equal(t, "hello", truncateString("hello", 10))
+	assert.Equal(t, "п", truncateString("привет", 3)) // do not break runes
+}
@@ -0,0 +1,200 @@
+package otelzap
+
+import (
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// messageKey is the attribute key of the original message
+// if it differs from the event name.
+const messageKey = attribute.Key("log.message")
+
+// templateMissingKey is the attribute key of template placeholders
+// having no corresponding attribute.
+const templateMissingKey = attribute.Key("log.template.missing")
+
+// WithMessageTemplates enables message templates, e.g.:
+//
+//	logger.Info("user {user_id} purchased {sku}",
+//		zap.String("user_id", userID),
+//		zap.String("sku", sku))
+//
+// The template is used as the event name as is, improving grouping
+// in trace backends, and the rendered message is kept as "log.message"
+// attribute. The placeholders having no corresponding attribute are
+// listed in "log.template.missing" attribute. The message is a template
+// only if at least one "{key}" placeholder matches an attribute,
+// so messages containing JSON or struct dumps are kept as is.
+func WithMessageTemplates() Option {
+	return func(o *options) {
+		o.messageTemplates = true
+	}
+}
+
+// WithEventNameNormalizer guards the event names from high cardinality:
+// the log message is normalized (e.g. "user 42 not found" becomes
+// "user * not found") and used as the event name, while the original
+// message is kept as "log.message" attribute.
+// If normalize is nil then NormalizeMessage is used.
+func WithEventNameNormalizer(normalize func(message string) string) Option {
+	return func(o *options) {
+		if normalize == nil {
+			normalize = NormalizeMessage
+		}
+		o.normalizeName = normalize
+	}
+}
+
+// NormalizeMessage replaces the words containing digits (e.g. IDs,
+// numbers, UUIDs) with "*", so messages like "order 123 failed"
+// and "order 456 failed" have the same "order * failed" name.
+// The leading and trailing punctuation of such words is kept.
+func NormalizeMessage(message string) string {
+	if strings.IndexFunc(message, unicode.IsDigit) < 0 {
+		return message // fast path
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(message))
+	for len(message) != 0 {
+		// copy spaces as is
+		i := strings.IndexFunc(message, func(r rune) bool { return !unicode.IsSpace(r) })
+		if i < 0 {
+			i = len(message)
+		}
+		sb.WriteString(message[:i])
+		message = message[i:]
+
+		// the next word
+		j := strings.IndexFunc(message, unicode.IsSpace)
+		if j < 0 {
+			j = len(message)
+		}
+		word := message[:j]
+		message = message[j:]
+
+		if strings.IndexFunc(word, unicode.IsDigit) < 0 {
+			sb.WriteString(word)
+			continue // keep it
+		}
+
+		core := strings.TrimFunc(word, unicode.IsPunct)
+		k := strings.Index(word, core)
+		sb.WriteString(word[:k])
+		sb.WriteByte('*')
+		sb.WriteString(word[k+len(core):])
+	}
+
+	return sb.String()
+}
+
+// eventName gets the event name of the message.
+// The original message is appended to attributes if normalized.
+func (o *options) eventName(message string, attrs []attribute.KeyValue) (string, []attribute.KeyValue) {
+	if o.messageTemplates && isTemplate(message, attrs) {
+		rendered, missing := renderTemplate(message, attrs)
+		attrs = append(attrs, messageKey.String(rendered))
+		if len(missing) != 0 {
+			attrs = append(attrs, templateMissingKey.StringSlice(missing))
+		}
+		return message, attrs
+	}
+	if o.normalizeName == nil {
+		return message, attrs // as is
+	}
+
+	name := o.normalizeName(message)
+	if name == message {
+		return message, attrs // nothing changed
+	}
+
+	return name, append(attrs, messageKey.String(message))
+}
+
+// isTemplate checks if the message has at least one "{key}" placeholder
+// matching an attribute, so messages containing JSON or struct dumps
+// are not treated as templates.
+func isTemplate(message string, attrs []attribute.KeyValue) bool {
+	for {
+		i := strings.IndexByte(message, '{')
+		if i < 0 {
+			return false
+		}
+		j := strings.IndexByte(message[i:], '}')
+		if j < 0 {
+			return false
+		}
+		j += i
+
+		if key := message[i+1 : j]; isPlaceholder(key) {
+			if _, ok := findAttribute(attrs, attribute.Key(key)); ok {
+				return true
+			}
+		}
+		message = message[i+1:]
+	}
+}
+
+// isPlaceholder checks if the key is a valid placeholder identifier,
+// e.g. "user_id" or "http.method".
+func isPlaceholder(key string) bool {
+	for i, r := range key {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '.' || r == '-' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return key != ""
+}
+
+// renderTemplate substitutes "{key}" placeholders with attribute values.
+// The placeholders having no attribute are kept as is and reported.
+// The braces which are not placeholders (e.g. JSON) are kept as is.
+func renderTemplate(template string, attrs []attribute.KeyValue) (string, []string) {
+	var sb strings.Builder
+	var missing []string
+	for {
+		i := strings.IndexByte(template, '{')
+		if i < 0 {
+			break // no more placeholders
+		}
+		j := strings.IndexByte(template[i:], '}')
+		if j < 0 {
+			break // not closed
+		}
+		j += i
+
+		key := template[i+1 : j]
+		if !isPlaceholder(key) {
+			sb.WriteString(template[:i+1])
+			template = template[i+1:]
+			continue // not a placeholder
+		}
+
+		sb.WriteString(template[:i])
+		if value, ok := findAttribute(attrs, attribute.Key(key)); ok {
+			sb.WriteString(value.Emit())
+		} else {
+			sb.WriteString(template[i : j+1])
+			missing = append(missing, key)
+		}
+		template = template[j+1:]
+	}
+	sb.WriteString(template)
+
+	return sb.String(), missing
+}
+
+// findAttribute finds the last attribute with the key.
+func findAttribute(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
@@ -0,0 +1,91 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestNormalizeMessage unit tests for message normalization.
+func TestNormalizeMessage(t *testing.T) {
+	assert.Equal(t, "no digits", NormalizeMessage("no digits"))
+	assert.Equal(t, "", NormalizeMessage(""))
+	assert.Equal(t, "user * not found", NormalizeMessage("user 42 not found"))
+	assert.Equal(t, "order (*): *, retry  #*.", NormalizeMessage("order (123): 0b7c6f8e-1a2b-4c3d-9e8f-0a1b2c3d4e5f, retry  #3."))
+	assert.Equal(t, "GET /*", NormalizeMessage("GET /users/42"))
+}
+
+// TestEventName unit tests for event name guard.
+func TestEventName(t *testing.T) {
+	name, attrs := newOptions().eventName("user 42", nil)
+	assert.Equal(t, "user 42", name)
+	assert.Empty(t, attrs)
+
+	o := newOptions(WithEventNameNormalizer(nil))
+	name, attrs = o.eventName("no digits", nil)
+	assert.Equal(t, "no digits", name)
+	assert.Empty(t, attrs)
+
+	name, attrs = o.eventName("user 42", nil)
+	assert.Equal(t, "user *", name)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("log.message", "user 42")}, attrs)
+}
+
+// TestRenderTemplate unit tests for message templates.
+func TestRenderTemplate(t *testing.T) {
+	attrs := []attribute.KeyValue{
+		attribute.String("user_id", "foo"),
+		attribute.Int("count", 2),
+	}
+
+	msg, missing := renderTemplate("user {user_id} purchased {count} of {sku}", attrs)
+	assert.Equal(t, "user foo purchased 2 of {sku}", msg)
+	assert.Equal(t, []string{"sku"}, missing)
+
+	msg, missing = renderTemplate("not closed {user_id", attrs)
+	assert.Equal(t, "not closed {user_id", msg)
+	assert.Empty(t, missing)
+
+	o := newOptions(WithMessageTemplates(), WithEventNameNormalizer(nil))
+	name, out := o.eventName("user {user_id} purchased {count} items", attrs)
+	assert.Equal(t, "user {user_id} purchased {count} items", name)
+	assert.Equal(t, attribute.String("log.message", "user foo purchased 2 items"), out[len(out)-1])
+
+	name, out = o.eventName("{user_id} bought {sku}", attrs[:1])
+	assert.Equal(t, "{user_id} bought {sku}", name)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("user_id", "foo"),
+		attribute.String("log.message", "foo bought {sku}"),
+		attribute.StringSlice("log.template.missing", []string{"sku"}),
+	}, out)
+
+	// no matching placeholder, not a template
+	name, out = o.eventName("{sku} 1", nil)
+	assert.Equal(t, "{sku} *", name)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("log.message", "{sku} 1")}, out)
+
+	// JSON-bearing message
+	o = newOptions(WithMessageTemplates())
+	name, out = o.eventName(`payload {"count":2}`, attrs)
+	assert.Equal(t, `payload {"count":2}`, name)
+	assert.Equal(t, attrs, out)
+
+	name, out = o.eventName(`user {user_id} sent {"count":2}`, attrs)
+	assert.Equal(t, `user {user_id} sent {"count":2}`, name)
+	assert.Equal(t, attribute.String("log.message", `user foo sent {"count":2}`), out[len(out)-1])
+	assert.Len(t, out, 3) // no missing placeholders
+}
+
+// TestIsTemplate unit tests for template detection.
+func TestIsTemplate(t *testing.T) {
+	attrs := []attribute.KeyValue{attribute.String("http.method", "GET")}
+	assert.True(t, isTemplate("method {http.method}", attrs))
+	assert.True(t, isTemplate(`{"a":1} {http.method}`, attrs))
+	assert.False(t, isTemplate("method {other}", attrs))
+	assert.False(t, isTemplate(`{"http.method":1}`, attrs))
+	assert.False(t, isTemplate("{http.method", attrs))
+	assert.False(t, isTemplate("no braces", attrs))
+	assert.False(t, isPlaceholder("1abc"))
+	assert.False(t, isPlaceholder(""))
+}
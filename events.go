@@ -0,0 +1,202 @@
+package otelzap
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// EntryWithFields is a log entry with its fields.
+type EntryWithFields struct {
+	Entry  zapcore.Entry
+	Fields []zapcore.Field
+}
+
+// EventData is a span event converted from a log entry.
+type EventData struct {
+	Name       string
+	Time       time.Time
+	Attributes []attribute.KeyValue
+}
+
+// EventFromEntry converts the log entry and fields to a span event
+// the same way the span logger does with default options,
+// so other emitters (e.g. logs bridge, test recorder) can share it.
+func EventFromEntry(entry zapcore.Entry, fields []zapcore.Field) EventData {
+	attrs := make([]attribute.KeyValue, 0, 3+EstimateAttrs(nil, fields))
+	attrs = defaultCore.appendEvent(attrs, entry, fields)
+	return newEventData(entry, attrs)
+}
+
+// EventsFromEntries converts log entries to span events in bulk
+// (e.g. to replay logs), the same way the span logger does with
+// default options. The attributes of all events share a single
+// allocation instead of allocating a slice per entry. The span logger
+// flushes the tail buffer (see WithTailBuffer) and drains the async
+// queue (see WithAsync) the same way.
+func EventsFromEntries(entries []EntryWithFields) []EventData {
+	n := 0
+	for _, e := range entries {
+		n += defaultCore.estimateEvent(e.Fields)
+	}
+
+	events := make([]EventData, len(entries))
+	batch := make(eventBatch, 0, n)
+	for i, e := range entries {
+		events[i] = batch.event(defaultCore, e.Entry, e.Fields)
+	}
+
+	return events
+}
+
+// defaultCore converts the entries with default options.
+var defaultCore = zapSpanCore{opts: defaultOptions}
+
+// newEventData creates the event of the entry. The attributes
+// capacity is limited, so they never share capacity with others.
+func newEventData(entry zapcore.Entry, attrs []attribute.KeyValue) EventData {
+	return EventData{
+		Name:       entry.Message,
+		Time:       entry.Time,
+		Attributes: attrs[:len(attrs):len(attrs)],
+	}
+}
+
+// eventBatch converts the entries to events in bulk,
+// the attributes of all events share a single allocation.
+type eventBatch []attribute.KeyValue
+
+// event converts the entry and fields using the core.
+func (b *eventBatch) event(core zapSpanCore, entry zapcore.Entry, fields []zapcore.Field) EventData {
+	start := len(*b)
+	*b = core.appendEvent(*b, entry, fields)
+	return newEventData(entry, (*b)[start:])
+}
+
+// addEvents writes the buffered entries to the span as events
+// converted in bulk, the same way EventsFromEntries does.
+// The entry time is used as event timestamp if timestamps is true
+// or the entry time option is set (see WithEntryTime).
+func addEvents(items []bufferedEntry, timestamps bool) {
+	n := 0
+	for _, item := range items {
+		n += item.core.estimateEvent(item.fields)
+	}
+
+	batch := make(eventBatch, 0, n)
+	for _, item := range items {
+		event := batch.event(item.core, item.entry, item.fields)
+		var opts []trace.EventOption
+		if timestamps || item.core.opts.entryTime {
+			opts = []trace.EventOption{trace.WithTimestamp(event.Time)}
+		}
+		item.core.writeEvent(item.entry, item.fields, event.Attributes, nil, opts)
+	}
+}
+
+// estimateEvent estimates the number of the event attributes.
+func (zs zapSpanCore) estimateEvent(fields []zapcore.Field) int {
+	return 4 + len(zs.extra) + EstimateAttrs(zs.with, fields) // + level, logger, caller and stack
+}
+
+// appendEvent converts the log entry and all the fields into event
+// attributes, appending them to the provided slice. This is the only
+// conversion used by both the span core and EventFromEntry.
+func (zs zapSpanCore) appendEvent(attrs []attribute.KeyValue, entry zapcore.Entry, fields []zapcore.Field) []attribute.KeyValue {
+	o := zs.opts
+	start := len(attrs)
+	attrs = appendEntryAttributes(attrs, entry)
+	if o.loggerScope && entry.LoggerName != "" {
+		attrs = appendLoggerScope(attrs, entry.LoggerName)
+	}
+	attrs = append(attrs, zs.extra...)
+	attrs = o.appendLevelAttributes(attrs, entry.Level)
+	if o.callerAttrs {
+		attrs = o.appendCaller(attrs, entry.Caller)
+	}
+	if o.provenance {
+		attrs = appendProvenance(attrs, attrs[start:], ProvenanceExtra)
+	}
+
+	mid := len(attrs) // extra attributes go first, fields next
+	with := excludeOverridden(zs.with, fields)
+	switch {
+	case o.provenance:
+		attrs = append(attrs, o.attributesWithProvenance(with, fields,
+			o.fieldOrder == FieldOrderCallSiteFirst)...)
+	case o.fieldOrder == FieldOrderCallSiteFirst:
+		attrs = o.appendZapFields(attrs, fields...)
+		attrs = o.appendZapFields(attrs, with...)
+	default:
+		attrs = o.appendZapFields(attrs, with...)
+		attrs = o.appendZapFields(attrs, fields...)
+	}
+	if len(o.throttle) != 0 && zs.state != nil {
+		attrs = append(attrs[:mid], zs.state.throttle(attrs[mid:], o.throttle)...)
+	}
+	if o.compactFlags {
+		attrs = append(attrs[:mid], compactFlags(attrs[mid:])...)
+	}
+	if o.loggerNamespace && entry.LoggerName != "" {
+		o.prefixKeys(attrs[mid:], entry.LoggerName+".")
+	}
+	if o.jsonFields && len(attrs) > mid {
+		attrs = append(attrs[:mid], jsonObject(jsonFieldsKey, attrs[mid:]))
+	}
+	if o.stackMode != StackNone {
+		attrs = appendStacks(attrs, o.stackMode, entry, with, fields)
+	}
+	o.formatStacks(attrs[mid:])
+	if len(attrs) == mid && !o.deterministic {
+		return attrs // no fields, use extra attributes only
+	}
+	if o.maxEventBytes > 0 {
+		budget := o.maxEventBytes - attributesSize(attrs[start:mid])
+		attrs = append(attrs[:mid], applyBudget(attrs[mid:], budget, o.overflow)...)
+	}
+
+	if o.fieldOrder != FieldOrderExtraFirst {
+		rotateAttributes(attrs[start:], mid-start) // fields go first
+	}
+	if o.deterministic {
+		sortAttributes(attrs[start:])
+	}
+
+	return attrs
+}
+
+// rotateAttributes rotates attributes left by n in place.
+func rotateAttributes(attrs []attribute.KeyValue, n int) {
+	reverseAttributes(attrs[:n])
+	reverseAttributes(attrs[n:])
+	reverseAttributes(attrs)
+}
+
+// reverseAttributes reverses attributes in place.
+func reverseAttributes(attrs []attribute.KeyValue) {
+	for i, j := 0, len(attrs)-1; i < j; i, j = i+1, j-1 {
+		attrs[i], attrs[j] = attrs[j], attrs[i]
+	}
+}
+
+// Options gets the options to add the event to a span, e.g.:
+//
+//	span.AddEvent(event.Name, event.Options()...)
+func (e EventData) Options() []trace.EventOption {
+	opts := make([]trace.EventOption, 0, 2)
+	if !e.Time.IsZero() {
+		opts = append(opts, trace.WithTimestamp(e.Time))
+	}
+	return append(opts, trace.WithAttributes(e.Attributes...))
+}
+
+// appendEntryAttributes appends the attributes of the entry itself.
+func appendEntryAttributes(attrs []attribute.KeyValue, entry zapcore.Entry) []attribute.KeyValue {
+	attrs = append(attrs, attribute.Stringer("zap.level", entry.Level))
+	if entry.LoggerName != "" {
+		attrs = append(attrs, attribute.String("zap.logger_name", entry.LoggerName))
+	}
+	return attrs
+}
@@ -0,0 +1,156 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestEventsFromEntries unit tests for batch conversion.
+func TestEventsFromEntries(t *testing.T) {
+	ts := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	events := EventsFromEntries([]EntryWithFields{
+		{
+			Entry:  zapcore.Entry{Level: zapcore.InfoLevel, Message: "message 1", Time: ts},
+			Fields: []zapcore.Field{zap.Int("foo", 123)},
+		},
+		{
+			Entry:  zapcore.Entry{Level: zapcore.WarnLevel, LoggerName: "my", Message: "message 2", Time: ts.Add(time.Second)},
+			Fields: []zapcore.Field{zap.Inline(user{Name: "foo"})}, // more than estimated
+		},
+		{
+			Entry: zapcore.Entry{Level: zapcore.ErrorLevel, Message: "message 3", Time: ts.Add(2 * time.Second)},
+		},
+	})
+
+	assert.Equal(t, []EventData{
+		{
+			Name: "message 1",
+			Time: ts,
+			Attributes: []attribute.KeyValue{
+				attribute.String("zap.level", "info"),
+				attribute.Int("foo", 123),
+			},
+		},
+		{
+			Name: "message 2",
+			Time: ts.Add(time.Second),
+			Attributes: []attribute.KeyValue{
+				attribute.String("zap.level", "warn"),
+				attribute.String("zap.logger_name", "my"),
+				attribute.Bool("admin", false),
+				attribute.Int("age", 0),
+				attribute.String("name", "foo"),
+				attribute.StringSlice("roles", nil),
+			},
+		},
+		{
+			Name: "message 3",
+			Time: ts.Add(2 * time.Second),
+			Attributes: []attribute.KeyValue{
+				attribute.String("zap.level", "error"),
+			},
+		},
+	}, events)
+
+	assert.Empty(t, EventsFromEntries(nil))
+}
+
+// TestEventFromEntry unit tests for single entry conversion.
+func TestEventFromEntry(t *testing.T) {
+	ts := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	event := EventFromEntry(
+		zapcore.Entry{Level: zapcore.InfoLevel, LoggerName: "my", Message: "my message", Time: ts},
+		[]zapcore.Field{zap.Int("foo", 123)})
+	assert.Equal(t, EventData{
+		Name: "my message",
+		Time: ts,
+		Attributes: []attribute.KeyValue{
+			attribute.String("zap.level", "info"),
+			attribute.String("zap.logger_name", "my"),
+			attribute.Int("foo", 123),
+		},
+	}, event)
+
+	cfg := trace.NewEventConfig(event.Options()...)
+	assert.Equal(t, ts, cfg.Timestamp())
+	assert.Equal(t, event.Attributes, cfg.Attributes())
+
+	assert.Len(t, EventData{}.Options(), 1) // no timestamp
+
+	// entry stack
+	event = EventFromEntry(
+		zapcore.Entry{Level: zapcore.ErrorLevel, Message: "my message", Stack: "main.main\n\t/app/main.go:10"},
+		nil)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("zap.level", "error"),
+		attribute.String("code.stacktrace", "main.main\n\t/app/main.go:10"),
+	}, event.Attributes)
+}
+
+// eventsSpan is a recording span which keeps the event attributes.
+type eventsSpan struct {
+	trace.Span
+	attrs [][]attribute.KeyValue
+}
+
+// IsRecording always returns true.
+func (*eventsSpan) IsRecording() bool { return true }
+
+// AddEvent keeps the event attributes.
+func (s *eventsSpan) AddEvent(_ string, opts ...trace.EventOption) {
+	cfg := trace.NewEventConfig(opts...)
+	s.attrs = append(s.attrs, cfg.Attributes())
+}
+
+// TestEventFromEntrySpanCore checks the span core and EventFromEntry
+// produce the same attributes.
+func TestEventFromEntrySpanCore(t *testing.T) {
+	entry := zapcore.Entry{Level: zapcore.WarnLevel, LoggerName: "my", Message: "my message", Stack: "main.main"}
+	fields := []zapcore.Field{zap.Int("foo", 123), zap.String("bar", "hello")}
+
+	span := &eventsSpan{Span: trace.SpanFromContext(context.Background())}
+	core := zapSpanCore{level: zapcore.DebugLevel, span: span, opts: defaultOptions, state: newSpanState()}
+	_ = core.Write(entry, fields)
+	if assert.Len(t, span.attrs, 1) {
+		assert.Equal(t, EventFromEntry(entry, fields).Attributes, span.attrs[0])
+	}
+
+	// the same options
+	span.attrs = nil
+	core.opts = newOptions(WithLoggerNamespace(), WithInterner(NewInterner(16, 64)),
+		WithFieldOrder(FieldOrderExtraLast))
+	_ = core.Write(entry, fields)
+	if assert.Len(t, span.attrs, 1) {
+		assert.Equal(t, core.appendEvent(nil, entry, fields), span.attrs[0])
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.Int("my.foo", 123),
+			attribute.String("my.bar", "hello"),
+			attribute.String("code.stacktrace", "main.main"),
+			attribute.String("zap.level", "warn"),
+			attribute.String("zap.logger_name", "my"),
+		}, span.attrs[0])
+	}
+}
+
+// BenchmarkEventsFromEntries benchmarks batch conversion.
+func BenchmarkEventsFromEntries(b *testing.B) {
+	entries := make([]EntryWithFields, 100)
+	for i := range entries {
+		entries[i] = EntryWithFields{
+			Entry:  zapcore.Entry{Level: zapcore.InfoLevel, Message: "message"},
+			Fields: []zapcore.Field{zap.Int("foo", i), zap.String("bar", "hello")},
+		}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		EventsFromEntries(entries)
+	}
+}
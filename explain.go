@@ -0,0 +1,73 @@
+package otelzap
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// ConversionPath is the code path a field is converted by.
+type ConversionPath string
+
+// Known conversion paths.
+const (
+	PathSkip          ConversionPath = "skip"          // zap.Skip(), zap.Namespace()
+	PathField         ConversionPath = "field"         // typed ZAP field, e.g. zap.Int()
+	PathInline        ConversionPath = "inline"        // zap.Inline()
+	PathTypeSwitch    ConversionPath = "type switch"   // known Go type
+	PathTextMarshaler ConversionPath = "TextMarshaler" // encoding.TextMarshaler
+	PathStringer      ConversionPath = "Stringer"      // fmt.Stringer
+	PathReflection    ConversionPath = "reflection"    // reflected slice or array
+	PathJSON          ConversionPath = "JSON"          // JSON fallback
+	PathFmt           ConversionPath = "fmt"           // fmt.Sprint() as a final option
+)
+
+// ConversionReport describes how a ZAP field is converted.
+type ConversionReport struct {
+	Key        string
+	Type       zapcore.FieldType
+	Path       ConversionPath
+	Attributes []attribute.KeyValue
+	Elapsed    time.Duration // conversion time
+}
+
+// Explain converts the ZAP fields and reports, for each field,
+// which code path handled it and how long it took.
+// This is a diagnostic tool for tuning converters and finding slow paths,
+// it is not intended for production use.
+func Explain(fields ...zapcore.Field) []ConversionReport {
+	reports := make([]ConversionReport, 0, len(fields))
+	for _, field := range fields {
+		start := time.Now()
+		attrs, path := explainField(field)
+		reports = append(reports, ConversionReport{
+			Key:        field.Key,
+			Type:       field.Type,
+			Path:       path,
+			Attributes: attrs,
+			Elapsed:    time.Since(start),
+		})
+	}
+	return reports
+}
+
+// explainField converts a ZAP field and reports the conversion path.
+func explainField(field zapcore.Field) ([]attribute.KeyValue, ConversionPath) {
+	switch field.Type {
+	case zapcore.SkipType,
+		zapcore.NamespaceType:
+		return nil, PathSkip
+
+	case zapcore.ReflectType,
+		zapcore.ArrayMarshalerType,
+		zapcore.ObjectMarshalerType:
+		kv, path := anyAttribute(field.Key, field.Interface)
+		return []attribute.KeyValue{kv}, path
+
+	case zapcore.InlineMarshalerType:
+		return appendZapField(nil, field), PathInline
+	}
+
+	return appendZapField(nil, field), PathField
+}
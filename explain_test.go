@@ -0,0 +1,56 @@
+package otelzap_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestExplain(t *testing.T) {
+	type Request struct {
+		ID string `json:"id"`
+	}
+
+	reports := Explain(
+		zap.Int("int", 1),
+		zap.Skip(),
+		zap.Any("ints", []int{1, 2}),
+		zap.Any("ip", net.IPv4(1, 2, 3, 4)),
+		zap.Any("dur", time.Second),
+		zap.Reflect("strs", []string{"a"}),
+		zap.Any("req", Request{ID: "foo"}),
+		zap.Any("ch", make(chan int)),
+	)
+
+	type result struct {
+		Key  string
+		Type zapcore.FieldType
+		Path ConversionPath
+	}
+	var results []result
+	for _, r := range reports {
+		results = append(results, result{r.Key, r.Type, r.Path})
+		assert.GreaterOrEqual(t, r.Elapsed, time.Duration(0))
+	}
+	assert.Equal(t, []result{
+		{"int", zapcore.Int64Type, PathField},
+		{"", zapcore.SkipType, PathSkip},
+		{"ints", zapcore.ArrayMarshalerType, PathReflection}, // see zap.Ints()
+		{"ip", zapcore.StringerType, PathField},
+		{"dur", zapcore.DurationType, PathField},
+		{"strs", zapcore.ReflectType, PathTypeSwitch},
+		{"req", zapcore.ReflectType, PathJSON},
+		{"ch", zapcore.ReflectType, PathFmt},
+	}, results)
+
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("int", 1)}, reports[0].Attributes)
+	assert.Empty(t, reports[1].Attributes)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("req", `{"id":"foo"}`)}, reports[6].Attributes)
+}
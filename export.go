@@ -0,0 +1,142 @@
+package otelzap
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// eventExporter writes span events as JSON lines.
+type eventExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// WithEventExport also writes each span event as a JSON line to the writer
+// (e.g. a file), so what was attached to spans can be analyzed offline,
+// for example diffed between load test runs. The shape is similar to OTLP JSON:
+//
+//	{"traceId":"...","spanId":"...","name":"my message","timeUnixNano":"...",
+//	 "attributes":[{"key":"foo","value":{"intValue":"123"}}]}
+//
+// See ReadExportedEvents and DiffEvents to use the events as test fixtures.
+func WithEventExport(w io.Writer) Option {
+	return func(o *options) {
+		if w == nil {
+			o.export = nil
+			return
+		}
+		o.export = &eventExporter{enc: json.NewEncoder(w)}
+	}
+}
+
+// ExportedEvent is a span event written by WithEventExport.
+type ExportedEvent struct {
+	TraceID      string              `json:"traceId"`
+	SpanID       string              `json:"spanId"`
+	Name         string              `json:"name"`
+	TimeUnixNano string              `json:"timeUnixNano"`
+	Attributes   []ExportedAttribute `json:"attributes,omitempty"`
+}
+
+// ExportedAttribute is an OTLP-like attribute.
+type ExportedAttribute struct {
+	Key   string        `json:"key"`
+	Value ExportedValue `json:"value"`
+}
+
+// ExportedValue is an OTLP-like attribute value, only one field is set.
+// As in OTLP JSON, 64-bit integers are strings.
+type ExportedValue struct {
+	StringValue *string             `json:"stringValue,omitempty"`
+	BoolValue   *bool               `json:"boolValue,omitempty"`
+	IntValue    *string             `json:"intValue,omitempty"`
+	DoubleValue *float64            `json:"doubleValue,omitempty"`
+	ArrayValue  *ExportedArrayValue `json:"arrayValue,omitempty"`
+}
+
+// ExportedArrayValue is an OTLP-like array value.
+type ExportedArrayValue struct {
+	Values []ExportedValue `json:"values"`
+}
+
+// write writes a span event as a JSON line.
+func (ex *eventExporter) write(sc trace.SpanContext, name string, ts time.Time, attrs []attribute.KeyValue) {
+	ev := ExportedEvent{
+		Name:         name,
+		TimeUnixNano: strconv.FormatInt(ts.UnixNano(), 10),
+		Attributes:   exportAttributes(attrs),
+	}
+	if sc.HasTraceID() {
+		ev.TraceID = sc.TraceID().String()
+	}
+	if sc.HasSpanID() {
+		ev.SpanID = sc.SpanID().String()
+	}
+
+	ex.mu.Lock()
+	err := ex.enc.Encode(&ev)
+	ex.mu.Unlock()
+	if err != nil {
+		handleError(err)
+	}
+}
+
+// exportAttributes converts attributes into OTLP-like shape.
+func exportAttributes(attrs []attribute.KeyValue) []ExportedAttribute {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	out := make([]ExportedAttribute, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, ExportedAttribute{
+			Key:   string(kv.Key),
+			Value: exportValue(kv.Value),
+		})
+	}
+	return out
+}
+
+// exportValue converts attribute value into OTLP-like shape.
+func exportValue(v attribute.Value) ExportedValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return ExportedValue{BoolValue: &b}
+	case attribute.INT64:
+		s := strconv.FormatInt(v.AsInt64(), 10)
+		return ExportedValue{IntValue: &s}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return ExportedValue{DoubleValue: &f}
+	case attribute.STRING:
+		s := v.AsString()
+		return ExportedValue{StringValue: &s}
+	case attribute.BOOLSLICE:
+		return exportArray(v.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return exportArray(v.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return exportArray(v.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return exportArray(v.AsStringSlice(), attribute.StringValue)
+	}
+
+	s := v.Emit() // unknown, probably a new type
+	return ExportedValue{StringValue: &s}
+}
+
+// exportArray converts slice into OTLP-like array value.
+func exportArray[T any](items []T, value func(T) attribute.Value) ExportedValue {
+	values := make([]ExportedValue, 0, len(items))
+	for _, item := range items {
+		values = append(values, exportValue(value(item)))
+	}
+	return ExportedValue{ArrayValue: &ExportedArrayValue{Values: values}}
+}
@@ -0,0 +1,39 @@
+package otelzap
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestEventExporter unit tests for the event exporter.
+func TestEventExporter(t *testing.T) {
+	var buf bytes.Buffer
+	o := newOptions(WithEventExport(&buf))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3},
+		SpanID:  trace.SpanID{4, 5, 6},
+	})
+	ts := time.Unix(1, 500)
+	o.export.write(sc, "my message", ts, []attribute.KeyValue{
+		attribute.String("zap.level", "info"),
+		attribute.Int("foo", 123),
+		attribute.Bool("ok", true),
+		attribute.Float64("pi", 3.5),
+		attribute.StringSlice("bar", []string{"a", "b"}),
+	})
+	o.export.write(trace.SpanContext{}, "no attributes", ts, nil)
+
+	assert.Equal(t, `{"traceId":"01020300000000000000000000000000","spanId":"0405060000000000","name":"my message","timeUnixNano":"1000000500",`+
+		`"attributes":[{"key":"zap.level","value":{"stringValue":"info"}},{"key":"foo","value":{"intValue":"123"}},`+
+		`{"key":"ok","value":{"boolValue":true}},{"key":"pi","value":{"doubleValue":3.5}},`+
+		`{"key":"bar","value":{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}}]}`+"\n"+
+		`{"traceId":"","spanId":"","name":"no attributes","timeUnixNano":"1000000500"}`+"\n", buf.String())
+
+	assert.Nil(t, newOptions(WithEventExport(&buf), WithEventExport(nil)).export)
+}
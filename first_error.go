@@ -0,0 +1,62 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// First error span attribute keys.
+const (
+	firstErrorPrefix     = "first_error."
+	firstErrorMessageKey = attribute.Key(firstErrorPrefix + "message")
+	firstErrorAtKey      = attribute.Key(firstErrorPrefix + "at")
+)
+
+// WithFirstError captures the first Error+ entry as span attributes:
+// "first_error.message", "first_error.at" (the caller, if known) and
+// "first_error.<key>" for each of the key attributes, if present.
+// Subsequent errors are ignored, so the span has compact triage data.
+func WithFirstError(keys ...string) Option {
+	return func(o *options) {
+		o.firstError = true
+		o.firstErrorKeys = append(o.firstErrorKeys, keys...)
+	}
+}
+
+// captureFirstError sets the span attributes of the first error entry.
+func (zs zapSpanCore) captureFirstError(entry zapcore.Entry, fields []zapcore.Field) {
+	st := zs.state
+	st.mu.Lock()
+	captured := st.firstError
+	st.firstError = true
+	st.mu.Unlock()
+	if captured {
+		return // not the first one
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 2+len(zs.opts.firstErrorKeys))
+	attrs = append(attrs, firstErrorMessageKey.String(entry.Message))
+	if entry.Caller.Defined {
+		at := entry.Caller.TrimmedPath()
+		if len(zs.opts.sourceRoots) != 0 {
+			at = zs.opts.trimSourcePath(entry.Caller.FullPath())
+		}
+		attrs = append(attrs, firstErrorAtKey.String(at))
+	}
+	if len(zs.opts.firstErrorKeys) != 0 {
+		all := zs.opts.attributesFromZapFields(excludeOverridden(zs.with, fields), fields)
+		for _, key := range zs.opts.firstErrorKeys {
+			for _, kv := range all {
+				if string(kv.Key) == key {
+					attrs = append(attrs, attribute.KeyValue{
+						Key:   attribute.Key(firstErrorPrefix + key),
+						Value: kv.Value,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	zs.span.SetAttributes(attrs...)
+}
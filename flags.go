@@ -0,0 +1,35 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// FlagsKey is the key of the compacted boolean flags attribute.
+const FlagsKey = "flags"
+
+// WithFlagCompaction collects the keys of all boolean-true fields
+// of an entry into the single "flags" string slice attribute
+// (e.g. ["cache_hit","retry"]) to reduce attribute count on flag-heavy events.
+// Boolean-false fields are kept as is.
+func WithFlagCompaction() Option {
+	return func(o *options) {
+		o.compactFlags = true
+	}
+}
+
+// compactFlags replaces the boolean-true attributes by the flags attribute.
+func compactFlags(attrs []attribute.KeyValue) []attribute.KeyValue {
+	var flags []string
+	out := attrs[:0]
+	for _, kv := range attrs {
+		if kv.Value.Type() == attribute.BOOL && kv.Value.AsBool() {
+			flags = append(flags, string(kv.Key))
+			continue
+		}
+		out = append(out, kv)
+	}
+	if len(flags) == 0 {
+		return out
+	}
+	return append(out, attribute.StringSlice(FlagsKey, flags))
+}
@@ -0,0 +1,171 @@
+package otelzap
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxFlattenDepth limits the depth of nested structs flattening.
+const maxFlattenDepth = 8
+
+// WithFlattenStructs flattens structs (see zap.Any and zap.Reflect)
+// into separate attributes, one per exported field, named as
+// "<key>.<field>" (e.g. "user.name", "user.address.city")
+// instead of a single JSON attribute.
+//
+// The `otel` struct tag can be used to rename the attribute,
+// omit zero values or opt the field out (the `json` tag
+// is used the same way if there is no `otel` tag):
+//
+//	type User struct {
+//		Name     string `otel:"name"`
+//		Email    string `otel:"email,omitempty"`
+//		Password string `otel:"-"`
+//	}
+func WithFlattenStructs() Option {
+	return func(o *options) {
+		o.flattenStructs = true
+	}
+}
+
+// appendFlattenedField converts and appends a ZAP field,
+// flattening the struct values.
+func appendFlattenedField(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	if field.Type == zapcore.ReflectType && field.Interface != nil {
+		if rv, ok := flattenable(reflect.ValueOf(field.Interface)); ok {
+			return appendFlattened(attributes, field.Key, rv, 0)
+		}
+	}
+
+	return appendZapField(attributes, field)
+}
+
+// flattenable checks if value is a struct (or non-nil pointer to a struct)
+// without custom marshaling, and gets that struct.
+func flattenable(rv reflect.Value) (reflect.Value, bool) {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, false
+	}
+
+	if customMarshaling(rv.Type()) {
+		return rv, false
+	}
+	if rv.CanAddr() && customMarshaling(reflect.PtrTo(rv.Type())) {
+		return rv, false
+	}
+
+	return rv, true
+}
+
+// Types used to detect custom marshaling.
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// customMarshaling checks if type has custom marshaling.
+// Types are checked instead of values since values of
+// unexported embedded fields cannot be interfaced.
+func customMarshaling(rt reflect.Type) bool {
+	return rt.Implements(jsonMarshalerType) ||
+		rt.Implements(textMarshalerType) ||
+		rt.Implements(stringerType)
+}
+
+// appendFlattened appends all the exported fields of the struct.
+// As encoding/json does, the exported fields of embedded structs
+// are promoted even if the embedded struct itself is unexported.
+func appendFlattened(attributes []attribute.KeyValue, prefix string, rv reflect.Value, depth int) []attribute.KeyValue {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue // private
+		}
+
+		name, omit, skip := parseFieldTag(sf)
+		if skip {
+			continue // opted out
+		}
+
+		fv := rv.Field(i)
+		if omit != nil && omit(fv) {
+			continue // zero value
+		}
+
+		nested, ok := flattenable(fv)
+		if ok && depth >= maxFlattenDepth {
+			ok = false // too deep, use JSON
+		}
+		switch {
+		case ok && sf.Anonymous && name == sf.Name:
+			attributes = appendFlattened(attributes, prefix, nested, depth+1) // embedded
+		case ok:
+			attributes = appendFlattened(attributes, prefix+"."+name, nested, depth+1)
+		case !fv.CanInterface():
+			continue // unexported embedded non-struct
+		default:
+			attributes = append(attributes, Any(prefix+"."+name, fv.Interface()))
+		}
+	}
+
+	return attributes
+}
+
+// parseFieldTag parses the `otel:"name,omitempty"` struct tag
+// or, if there is no such tag, the `json:"name,omitempty"` struct tag.
+// The omit function is nil if the field should not be omitted.
+func parseFieldTag(sf reflect.StructField) (name string, omit func(reflect.Value) bool, skip bool) {
+	tag, ok := sf.Tag.Lookup("otel")
+	omitEmpty := reflect.Value.IsZero
+	if !ok {
+		tag, ok = sf.Tag.Lookup("json")
+		omitEmpty = isEmptyValue // as encoding/json does
+	}
+	if tag == "-" {
+		return "", nil, true
+	}
+
+	name = sf.Name
+	if ok {
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			name = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				omit = omitEmpty
+			}
+		}
+	}
+
+	return name, omit, false
+}
+
+// isEmptyValue checks if value is empty in terms of JSON omitempty.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return rv.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package otelzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestFlattenStructs unit tests for struct flattening.
+func TestFlattenStructs(t *testing.T) {
+	type (
+		Address struct {
+			City string `otel:"city"`
+			Zip  string `otel:"zip,omitempty"`
+		}
+		Base struct {
+			ID int `otel:"id"`
+		}
+		User struct {
+			Base
+			Name     string   `otel:"name"`
+			Password string   `otel:"-"`
+			Address  *Address `otel:"address"`
+			Home     *Address `otel:"home,omitempty"`
+			Tags     []string
+			Created  time.Time `otel:"created"`
+			private  int
+		}
+	)
+
+	created := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	u := User{
+		Base:     Base{ID: 1},
+		Name:     "foo",
+		Password: "secret",
+		Address:  &Address{City: "Paris"},
+		Tags:     []string{"a", "b"},
+		Created:  created,
+		private:  1,
+	}
+
+	o := newOptions(WithFlattenStructs())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int("user.id", 1),
+		attribute.String("user.name", "foo"),
+		attribute.String("user.address.city", "Paris"),
+		attribute.StringSlice("user.Tags", []string{"a", "b"}),
+		Any("user.created", created),
+	}, o.appendZapFields(nil, zap.Any("user", &u)))
+
+	// not flattened
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("str", "foo"),
+		Any("time", created),
+		attribute.IntSlice("ints", []int{1, 2}),
+	}, o.appendZapFields(nil,
+		zap.Any("str", "foo"),
+		zap.Reflect("time", created),
+		zap.Any("ints", []int{1, 2})))
+}
+
+// TestFlattenJSONTags unit tests for struct flattening with JSON tags.
+func TestFlattenJSONTags(t *testing.T) {
+	type (
+		Meta struct {
+			Version int `json:"version,omitempty"`
+		}
+		Order struct {
+			Meta
+			ID     string   `json:"id"`
+			Secret string   `json:"-"`
+			Items  []string `json:"items,omitempty"`
+			Note   string   `json:"note,omitempty" otel:"comment"` // otel tag wins
+			Total  Meta     `json:"total,omitempty"`               // structs are never empty
+		}
+	)
+
+	order := Order{
+		Meta:   Meta{Version: 2},
+		ID:     "foo",
+		Secret: "secret",
+	}
+
+	o := newOptions(WithFlattenStructs())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int("order.version", 2),
+		attribute.String("order.id", "foo"),
+		attribute.String("order.comment", ""),
+	}, o.appendZapFields(nil, zap.Any("order", order)))
+
+	// JSON fallback is consistent
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("order", `{"version":2,"id":"foo","total":{}}`),
+	}, appendZapField(nil, zap.Any("order", order)))
+}
+
+// TestFlattenUnexportedEmbedded unit tests for unexported embedded structs.
+func TestFlattenUnexportedEmbedded(t *testing.T) {
+	type (
+		inner struct {
+			A       int `json:"a"`
+			private int
+		}
+		meta struct {
+			V int `json:"v"`
+		}
+		number int
+		Outer  struct {
+			inner
+			*meta
+			number
+		}
+	)
+
+	outer := Outer{inner: inner{A: 1, private: 2}, meta: &meta{V: 3}, number: 4}
+
+	o := newOptions(WithFlattenStructs())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int("outer.a", 1),
+		attribute.Int("outer.v", 3),
+	}, o.appendZapFields(nil, zap.Any("outer", outer)))
+
+	// JSON fallback is consistent
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("outer", `{"a":1,"v":3}`),
+	}, appendZapField(nil, zap.Any("outer", outer)))
+
+	// nil embedded pointer is skipped
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int("outer.a", 1),
+	}, o.appendZapFields(nil, zap.Any("outer", Outer{inner: inner{A: 1}})))
+}
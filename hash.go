@@ -0,0 +1,46 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithHashKeys replaces the values of fields with the keys by their
+// "sha256:<hex>" hash and adds the "<key>.length" attribute, so two spans
+// can be proven to see the same (large or sensitive) value
+// without storing the value itself.
+//
+// The strings and byte slices are hashed as is,
+// other values are hashed as converted attribute values.
+func WithHashKeys(keys ...string) Option {
+	return func(o *options) {
+		if o.hashKeys == nil {
+			o.hashKeys = make(map[string]bool, len(keys))
+		}
+		for _, key := range keys {
+			o.hashKeys[key] = true
+		}
+	}
+}
+
+// appendHashed appends the hash and length attributes of the field.
+func appendHashed(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	switch field.Type {
+	case zapcore.StringType:
+		return appendHash(attributes, field.Key, []byte(field.String))
+	case zapcore.BinaryType, zapcore.ByteStringType:
+		return appendHash(attributes, field.Key, field.Interface.([]byte))
+	}
+
+	for _, kv := range appendZapField(nil, field) {
+		attributes = appendHash(attributes, string(kv.Key), []byte(kv.Value.Emit()))
+	}
+	return attributes
+}
+
+// appendHash appends the hash and length attributes of the data.
+func appendHash(attributes []attribute.KeyValue, key string, data []byte) []attribute.KeyValue {
+	return append(attributes,
+		attribute.String(key, hashValue(data)),
+		attribute.Int(key+lengthSuffix, len(data)))
+}
@@ -0,0 +1,39 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestHashKeys unit tests for WithHashKeys option.
+func TestHashKeys(t *testing.T) {
+	const hello = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	o := newOptions(WithHashKeys("body", "raw"), WithHashKeys("n"))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("body", hello),
+			attribute.Int("body.length", 5),
+			attribute.String("raw", hello),
+			attribute.Int("raw.length", 5),
+			attribute.String("n", hashValue([]byte("123"))),
+			attribute.Int("n.length", 3),
+			attribute.String("foo", "bar"),
+		},
+		o.appendZapFields(nil,
+			zap.String("body", "hello"),
+			zap.ByteString("raw", []byte("hello")),
+			zap.Int("n", 123),
+			zap.String("foo", "bar")))
+
+	// same hash when interned
+	o = newOptions(WithHashKeys("raw"), WithInterner(NewInterner(16, 64)))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("raw", hello),
+			attribute.Int("raw.length", 5),
+		},
+		o.appendZapFields(nil, zap.ByteString("raw", []byte("hello"))))
+}
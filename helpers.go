@@ -0,0 +1,57 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// AddEvent adds an event with ZAP fields converted to attributes
+// to the span, without constructing a logger.
+func AddEvent(span trace.Span, msg string, fields ...zapcore.Field) {
+	if span == nil || !span.IsRecording() {
+		return // no tracing enabled
+	}
+
+	span.AddEvent(msg, trace.WithAttributes(attributesFromZapFields(nil, fields)...))
+}
+
+// RecordError records the error as an exception event with ZAP fields
+// converted to attributes and sets the span status to error.
+// Nothing is recorded if err is nil.
+func RecordError(span trace.Span, err error, fields ...zapcore.Field) {
+	if err == nil || span == nil || !span.IsRecording() {
+		return // nothing to record
+	}
+
+	span.RecordError(err, trace.WithAttributes(attributesFromZapFields(nil, fields)...))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// StartOptions converts ZAP fields to span start options,
+// so spans can be started with the same fields used for logging:
+//
+//	ctx, span := tracer.Start(ctx, "name", otelzap.StartOptions(zap.String("user", user))...)
+func StartOptions(fields ...zapcore.Field) []trace.SpanStartOption {
+	if len(fields) == 0 {
+		return nil // nothing to add
+	}
+
+	return []trace.SpanStartOption{
+		trace.WithAttributes(attributesFromZapFields(nil, fields)...),
+	}
+}
+
+// LogEvent writes the log entry and fields to the span as an event,
+// exactly as the span logger does with default options, passing
+// the event options (e.g. trace.WithTimestamp or trace.WithStackTrace) through.
+func LogEvent(span trace.Span, entry zapcore.Entry, fields []zapcore.Field, opts ...trace.EventOption) {
+	if span == nil || !span.IsRecording() {
+		return // no tracing enabled
+	}
+
+	event := EventFromEntry(entry, fields)
+	opts = append(opts[:len(opts):len(opts)], // do not modify the caller's slice
+		trace.WithAttributes(event.Attributes...))
+	span.AddEvent(event.Name, opts...)
+}
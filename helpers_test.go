@@ -0,0 +1,84 @@
+package otelzap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestAddEvent(t *testing.T) {
+	AddEvent(nil, "no span", zap.Int("foo", 123))
+
+	span := newRecordingSpan(t)
+	span.EXPECT().
+		AddEvent("my event",
+			trace.WithAttributes(
+				attribute.Int("foo", 123),
+				attribute.String("bar", "hello"),
+			))
+	AddEvent(span, "my event", zap.Int("foo", 123), zap.String("bar", "hello"))
+}
+
+func TestRecordError(t *testing.T) {
+	span := newRecordingSpan(t)
+	RecordError(span, nil, zap.Int("foo", 123)) // no error
+	RecordError(nil, assert.AnError)            // no span
+
+	span.EXPECT().
+		RecordError(assert.AnError,
+			trace.WithAttributes(
+				attribute.Int("foo", 123),
+			))
+	span.EXPECT().
+		SetStatus(codes.Error, assert.AnError.Error())
+	RecordError(span, assert.AnError, zap.Int("foo", 123))
+}
+
+func TestStartOptions(t *testing.T) {
+	assert.Nil(t, StartOptions())
+
+	cfg := trace.NewSpanStartConfig(StartOptions(zap.Int("foo", 123), zap.Bool("bar", true))...)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int("foo", 123),
+		attribute.Bool("bar", true),
+	}, cfg.Attributes())
+}
+
+func TestLogEvent(t *testing.T) {
+	LogEvent(nil, zapcore.Entry{}, nil) // no span
+
+	span := newRecordingSpan(t)
+	ts := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithTimestamp(ts),
+			trace.WithAttributes(
+				attribute.String("zap.level", "warn"),
+				attribute.String("zap.logger_name", "my"),
+				attribute.Int("foo", 123),
+			))
+	LogEvent(span,
+		zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			LoggerName: "my",
+			Message:    "my message",
+		},
+		[]zapcore.Field{zap.Int("foo", 123)},
+		trace.WithTimestamp(ts))
+
+	// the caller's options are not modified
+	opts := make([]trace.EventOption, 1, 2)
+	opts[0] = trace.WithTimestamp(ts)
+	span.EXPECT().AddEvent("my message", gomock.Any(), gomock.Any())
+	LogEvent(span, zapcore.Entry{Message: "my message"}, nil, opts...)
+	assert.Nil(t, opts[:2][1])
+}
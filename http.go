@@ -0,0 +1,100 @@
+package otelzap
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSpanKind sets the span kind (trace.SpanKindServer by default)
+// used by HTTP helpers to choose the span status (see StatusFromHTTPKind)
+// and the semantic convention attributes.
+// Use trace.SpanKindClient for outbound calls.
+func WithSpanKind(kind trace.SpanKind) Option {
+	return func(o *options) {
+		o.spanKind = kind
+	}
+}
+
+// kind gets the span kind, server by default.
+func (o *options) kind() trace.SpanKind {
+	if o.spanKind == trace.SpanKindUnspecified {
+		return trace.SpanKindServer
+	}
+	return o.spanKind
+}
+
+// HTTPRequest creates the fields of the HTTP request according to
+// the semantic conventions of the span kind: for servers these are
+// "http.target", "net.peer.ip" (the client) etc., for clients these are
+// "http.url", "net.peer.name" and "net.peer.port" (the server) etc.
+func HTTPRequest(r *http.Request, kind trace.SpanKind) []zapcore.Field {
+	var attrs []attribute.KeyValue
+	if kind == trace.SpanKindClient {
+		attrs = semconv.HTTPClientAttributesFromHTTPRequest(r)
+		attrs = appendPeerAttributes(attrs, r)
+	} else {
+		attrs = semconv.HTTPServerAttributesFromHTTPRequest("", "", r)
+		attrs = append(attrs, semconv.NetAttributesFromHTTPRequest("tcp", r)...)
+	}
+
+	return fieldsFromAttributes(attrs)
+}
+
+// HTTPResponse creates the fields of the HTTP response: the status code
+// and the elapsed time as "http.server.duration" or "http.client.duration"
+// depending on the span kind.
+func HTTPResponse(code int, elapsed time.Duration, kind trace.SpanKind) []zapcore.Field {
+	key := "http.server.duration"
+	if kind == trace.SpanKindClient {
+		key = "http.client.duration"
+	}
+
+	return []zapcore.Field{
+		zap.Int(string(semconv.HTTPStatusCodeKey), code),
+		zap.Duration(key, elapsed),
+	}
+}
+
+// appendPeerAttributes appends the server address of the outbound request.
+func appendPeerAttributes(attrs []attribute.KeyValue, r *http.Request) []attribute.KeyValue {
+	host, port := r.URL.Host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host, port = h, p
+	}
+	if host != "" {
+		attrs = append(attrs, semconv.NetPeerNameKey.String(host))
+	}
+	if n, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(n))
+	}
+	return attrs
+}
+
+// fieldsFromAttributes converts OpenTelemetry attributes into ZAP fields.
+func fieldsFromAttributes(attrs []attribute.KeyValue) []zapcore.Field {
+	fields := make([]zapcore.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		key := string(attr.Key)
+		switch attr.Value.Type() {
+		case attribute.BOOL:
+			fields = append(fields, zap.Bool(key, attr.Value.AsBool()))
+		case attribute.INT64:
+			fields = append(fields, zap.Int64(key, attr.Value.AsInt64()))
+		case attribute.FLOAT64:
+			fields = append(fields, zap.Float64(key, attr.Value.AsFloat64()))
+		case attribute.STRING:
+			fields = append(fields, zap.String(key, attr.Value.AsString()))
+		default: // slices
+			fields = append(fields, zap.Any(key, attr.Value.AsInterface()))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,181 @@
+package otelzap
+
+import (
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// headerNewlineToSpace sanitizes header values as http.Header.Write does.
+var headerNewlineToSpace = strings.NewReplacer("\n", " ", "\r", " ")
+
+// WithRawHeaderCase keeps the original casing of HTTP header names
+// in HTTPHeader and HTTPHeaderAttributes output,
+// instead of the canonical form (see textproto.CanonicalMIMEHeaderKey).
+func WithRawHeaderCase() Option {
+	return func(o *options) {
+		o.rawHeaderCase = true
+	}
+}
+
+// WithTrailer also includes the HTTP trailers (see http.Request.Trailer
+// and http.Response.Trailer) in HTTPHeader and HTTPHeaderAttributes output:
+// the trailers follow the headers after an empty line, as on the wire,
+// and the per-trailer attributes are "<key>.trailer.<name>".
+// Should be used after the body is read, when the trailers are known.
+func WithTrailer(trailer http.Header) Option {
+	return func(o *options) {
+		o.trailer = trailer
+	}
+}
+
+// pseudoHeaderKeys are the attribute keys of HTTP/2 pseudo-headers.
+var pseudoHeaderKeys = map[string]attribute.Key{
+	":authority": semconv.HTTPHostKey,
+	":path":      semconv.HTTPTargetKey,
+	":method":    semconv.HTTPMethodKey,
+	":scheme":    semconv.HTTPSchemeKey,
+	":status":    semconv.HTTPStatusCodeKey,
+}
+
+// headerEntry is a sanitized HTTP header.
+type headerEntry struct {
+	name   string
+	values []string
+}
+
+// HTTPHeader converts HTTP headers into OpenTelemetry attribute as multi-line string.
+// The HTTP headers to exclude should be in canonical form (see textproto.CanonicalMIMEHeaderKey).
+//
+// The output is deterministic: the header names are canonical and sorted,
+// the values of the same header keep their order. The values of names
+// which differ by case only (e.g. set directly to the map) are merged.
+//
+// The HTTP/2 pseudo-headers (e.g. ":authority" and ":path" of h2 metadata)
+// are kept as is and go first. See also WithTrailer and WithRawHeaderCase.
+func HTTPHeader(key string, header http.Header, exclude map[string]bool, opts ...Option) attribute.KeyValue {
+	o := newOptions(opts...)
+	s := headerString(headerEntries(header, exclude, o.rawHeaderCase))
+	if o.trailer != nil {
+		s += "\r\n" + headerString(headerEntries(o.trailer, exclude, o.rawHeaderCase))
+	}
+	return attribute.String(key, s)
+}
+
+// HTTPHeaderAttributes converts HTTP headers in a single pass into both
+// the multi-line string field (for the log line, see HTTPHeader)
+// and the per-header attributes (for the span), e.g. for the key
+// "http.request.header" the "Content-Type" header becomes
+// "http.request.header.content_type" string slice attribute
+// according to the semantic conventions.
+// The HTTP/2 pseudo-headers become the semantic convention attributes,
+// e.g. ":authority" is "http.host" and ":path" is "http.target".
+// The HTTP headers to exclude should be in canonical form (see textproto.CanonicalMIMEHeaderKey).
+func HTTPHeaderAttributes(key string, header http.Header, exclude map[string]bool, opts ...Option) (zapcore.Field, []attribute.KeyValue) {
+	o := newOptions(opts...)
+	entries := headerEntries(header, exclude, o.rawHeaderCase)
+	var trailers []headerEntry
+	if o.trailer != nil {
+		trailers = headerEntries(o.trailer, exclude, o.rawHeaderCase)
+	}
+	if len(entries)+len(trailers) == 0 {
+		return zap.String(key, ""), nil
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(entries)+len(trailers))
+	for _, e := range entries {
+		attrs = append(attrs, headerAttribute(key, e))
+	}
+	for _, e := range trailers {
+		attrs = append(attrs, headerAttribute(key+".trailer", e))
+	}
+
+	s := headerString(entries)
+	if o.trailer != nil {
+		s += "\r\n" + headerString(trailers)
+	}
+	return zap.String(key, s), attrs
+}
+
+// headerAttribute converts the header into attribute.
+func headerAttribute(prefix string, e headerEntry) attribute.KeyValue {
+	if !strings.HasPrefix(e.name, ":") {
+		return attribute.StringSlice(headerAttributeKey(prefix, e.name), e.values)
+	}
+
+	// HTTP/2 pseudo-header
+	if key, ok := pseudoHeaderKeys[e.name]; ok && len(e.values) == 1 {
+		if key == semconv.HTTPStatusCodeKey {
+			if code, err := strconv.Atoi(e.values[0]); err == nil {
+				return key.Int(code)
+			}
+		}
+		return key.String(e.values[0])
+	}
+	return attribute.StringSlice(headerAttributeKey(prefix, e.name[1:]), e.values)
+}
+
+// headerEntries gets the sanitized headers sorted by name, excluding some.
+func headerEntries(header http.Header, exclude map[string]bool, raw bool) []headerEntry {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // stable order of merged values
+
+	entries := make([]headerEntry, 0, len(keys))
+	index := make(map[string]int, len(keys))
+	for _, k := range keys {
+		canonical := textproto.CanonicalMIMEHeaderKey(k) // pseudo-headers as is
+		if exclude[canonical] || exclude[k] {
+			continue // excluded
+		}
+
+		name := canonical
+		if raw {
+			name = k
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(entries)
+			index[name] = i
+			entries = append(entries, headerEntry{name: name})
+		}
+		for _, v := range header[k] {
+			v = textproto.TrimString(headerNewlineToSpace.Replace(v))
+			entries[i].values = append(entries[i].values, v)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+// headerString formats headers as multi-line string, see http.Header.Write.
+func headerString(entries []headerEntry) string {
+	var sb strings.Builder
+	for _, e := range entries {
+		for _, v := range e.values {
+			sb.WriteString(e.name)
+			sb.WriteString(": ")
+			sb.WriteString(v)
+			sb.WriteString("\r\n")
+		}
+	}
+	return sb.String()
+}
+
+// headerAttributeKey gets the attribute key of the header,
+// lowercase with dashes replaced by underscores.
+func headerAttributeKey(prefix, header string) string {
+	return prefix + "." + strings.ReplaceAll(strings.ToLower(header), "-", "_")
+}
@@ -0,0 +1,78 @@
+package otelzap_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestHTTPHeaderAttributes(t *testing.T) {
+	field, attrs := HTTPHeaderAttributes("foo", nil, nil)
+	assert.Equal(t, zap.String("foo", ""), field)
+	assert.Empty(t, attrs)
+
+	h := http.Header{}
+	h.Add("content-type", "application/json")
+	h.Add("authorization", "Bearer ups")
+	h.Add("x-multi", "1")
+	h.Add("x-multi", " 2\n")
+	field, attrs = HTTPHeaderAttributes("http.request.header", h, map[string]bool{"Authorization": true})
+	assert.Equal(t, zap.String("http.request.header",
+		"Content-Type: application/json\r\nX-Multi: 1\r\nX-Multi: 2\r\n"), field)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("http.request.header.content_type", []string{"application/json"}),
+		attribute.StringSlice("http.request.header.x_multi", []string{"1", "2"}),
+	}, attrs)
+
+	// canonical and deterministic
+	h = http.Header{"x-foo": {"2"}, "X-Foo": {"1"}, "b": {"x"}, "A": {"y"}}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, attribute.String("foo", "A: y\r\nB: x\r\nX-Foo: 1\r\nX-Foo: 2\r\n"),
+			HTTPHeader("foo", h, nil))
+	}
+	assert.Equal(t, attribute.String("foo", "A: y\r\nX-Foo: 1\r\nb: x\r\nx-foo: 2\r\n"),
+		HTTPHeader("foo", h, nil, WithRawHeaderCase()))
+	assert.Equal(t, attribute.String("foo", "A: y\r\n"),
+		HTTPHeader("foo", h, map[string]bool{"X-Foo": true, "B": true}))
+
+	// the same as HTTPHeader
+	assert.Equal(t, HTTPHeader("foo", h, nil).Value.AsString(),
+		func() string { f, _ := HTTPHeaderAttributes("foo", h, nil); return f.String }())
+}
+
+func TestHTTPHeaderTrailersAndPseudoHeaders(t *testing.T) {
+	h2 := http.Header{
+		":authority":   {"example.com"},
+		":path":        {"/foo?bar=1"},
+		":status":      {"200"},
+		":custom":      {"x"},
+		"content-type": {"application/grpc"},
+	}
+	trailer := http.Header{"Grpc-Status": {"0"}}
+
+	assert.Equal(t, attribute.String("h",
+		":authority: example.com\r\n:custom: x\r\n:path: /foo?bar=1\r\n:status: 200\r\n"+
+			"Content-Type: application/grpc\r\n\r\nGrpc-Status: 0\r\n"),
+		HTTPHeader("h", h2, nil, WithTrailer(trailer)))
+
+	field, attrs := HTTPHeaderAttributes("h", h2, nil, WithTrailer(trailer))
+	assert.Equal(t, HTTPHeader("h", h2, nil, WithTrailer(trailer)).Value.AsString(), field.String)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("http.host", "example.com"),
+		attribute.StringSlice("h.custom", []string{"x"}),
+		attribute.String("http.target", "/foo?bar=1"),
+		attribute.Int("http.status_code", 200),
+		attribute.StringSlice("h.content_type", []string{"application/grpc"}),
+		attribute.StringSlice("h.trailer.grpc_status", []string{"0"}),
+	}, attrs)
+
+	_, attrs = HTTPHeaderAttributes("h", nil, nil, WithTrailer(trailer))
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("h.trailer.grpc_status", []string{"0"}),
+	}, attrs)
+}
@@ -0,0 +1,124 @@
+package otelzap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StatusFromHTTP gets the span status of the HTTP response status code
+// according to the semantic conventions for HTTP servers:
+// 5xx and invalid status codes are errors, others leave status unset.
+// See also StatusFromHTTPKind for HTTP clients.
+func StatusFromHTTP(code int) (codes.Code, string) {
+	return StatusFromHTTPKind(code, trace.SpanKindServer)
+}
+
+// StatusFromHTTPKind is similar to StatusFromHTTP but takes the span kind
+// into account: for clients (trace.SpanKindClient) 4xx are errors too.
+func StatusFromHTTPKind(code int, kind trace.SpanKind) (codes.Code, string) {
+	if code < 100 || code >= 600 {
+		return codes.Error, fmt.Sprintf("Invalid HTTP status code %d", code)
+	}
+	if code >= 500 || (code >= 400 && kind == trace.SpanKindClient) {
+		return codes.Error, http.StatusText(code)
+	}
+	return codes.Unset, ""
+}
+
+// levelFromHTTP gets the log level of the HTTP response status code:
+// 5xx are errors, 4xx are warnings, others are info.
+func levelFromHTTP(code int) zapcore.Level {
+	switch {
+	case code >= 500 || code < 100:
+		return zapcore.ErrorLevel
+	case code >= 400:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// WithHTTPStatus makes Middleware capture the response status code,
+// set the span status (see StatusFromHTTP) and log the
+// "HTTP request completed" entry with the level of the status class
+// (error for 5xx, warning for 4xx, info otherwise), so logs
+// and span status are aligned.
+func WithHTTPStatus() Option {
+	return func(o *options) {
+		o.httpStatus = true
+	}
+}
+
+// statusRecorder captures the response status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader captures the status code.
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Write captures the implicit http.StatusOK.
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client,
+// if the original response writer supports it (see http.Flusher).
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection, if the original response writer
+// supports it (see http.Hijacker), e.g. for websockets.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errHijackNotSupported
+}
+
+// errHijackNotSupported is returned if the connection cannot be hijacked.
+var errHijackNotSupported = errors.New("otelzap: response writer does not support hijacking")
+
+// Unwrap gets the original response writer (see http.ResponseController).
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// code gets the captured status code.
+func (r *statusRecorder) code() int {
+	if r.status == 0 {
+		return http.StatusOK // nothing written
+	}
+	return r.status
+}
+
+// logHTTPStatus logs the request completion with the status code.
+func logHTTPStatus(logger *zap.Logger, code int) {
+	if ce := logger.Check(levelFromHTTP(code), "HTTP request completed"); ce != nil {
+		ce.Write(zap.Int(string(semconv.HTTPStatusCodeKey), code))
+	}
+}
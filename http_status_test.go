@@ -0,0 +1,41 @@
+package otelzap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestStatusFromHTTP unit tests for HTTP status classification.
+func TestStatusFromHTTP(t *testing.T) {
+	for _, tc := range []struct {
+		code   int
+		status codes.Code
+		desc   string
+		level  zapcore.Level
+	}{
+		{http.StatusOK, codes.Unset, "", zapcore.InfoLevel},
+		{http.StatusFound, codes.Unset, "", zapcore.InfoLevel},
+		{http.StatusNotFound, codes.Unset, "", zapcore.WarnLevel},
+		{http.StatusInternalServerError, codes.Error, "Internal Server Error", zapcore.ErrorLevel},
+		{http.StatusServiceUnavailable, codes.Error, "Service Unavailable", zapcore.ErrorLevel},
+		{42, codes.Error, "Invalid HTTP status code 42", zapcore.ErrorLevel},
+		{600, codes.Error, "Invalid HTTP status code 600", zapcore.ErrorLevel},
+	} {
+		status, desc := StatusFromHTTP(tc.code)
+		assert.Equal(t, tc.status, status, tc.code)
+		assert.Equal(t, tc.desc, desc, tc.code)
+		assert.Equal(t, tc.level, levelFromHTTP(tc.code), tc.code)
+	}
+}
+
+// TestStatusRecorderHijack unit tests for hijacking unsupported by the writer.
+func TestStatusRecorderHijack(t *testing.T) {
+	rec := &statusRecorder{ResponseWriter: httptest.NewRecorder()}
+	_, _, err := rec.Hijack()
+	assert.ErrorIs(t, err, errHijackNotSupported)
+}
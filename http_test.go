@@ -0,0 +1,69 @@
+package otelzap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestHTTPRequest unit tests for HTTP request fields.
+func TestHTTPRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?id=1", nil)
+	r.RemoteAddr = "10.0.0.1:5555"
+	r.Header.Set("User-Agent", "test")
+	fields := fieldsMap(HTTPRequest(r, trace.SpanKindServer))
+	assert.Equal(t, "GET", fields["http.method"])
+	assert.Equal(t, "/users?id=1", fields["http.target"])
+	assert.Equal(t, "10.0.0.1", fields["net.peer.ip"])
+	assert.Equal(t, "test", fields["http.user_agent"])
+	assert.NotContains(t, fields, "http.url")
+
+	r, err := http.NewRequest(http.MethodPost, "https://example.com:8443/api", nil)
+	assert.NoError(t, err)
+	fields = fieldsMap(HTTPRequest(r, trace.SpanKindClient))
+	assert.Equal(t, "POST", fields["http.method"])
+	assert.Equal(t, "https://example.com:8443/api", fields["http.url"])
+	assert.Equal(t, "example.com", fields["net.peer.name"])
+	assert.Equal(t, int64(8443), fields["net.peer.port"])
+	assert.NotContains(t, fields, "http.target")
+}
+
+// TestHTTPResponse unit tests for HTTP response fields.
+func TestHTTPResponse(t *testing.T) {
+	assert.Equal(t, []zapcore.Field{
+		zap.Int("http.status_code", 200),
+		zap.Duration("http.server.duration", time.Second),
+	}, HTTPResponse(200, time.Second, trace.SpanKindServer))
+	assert.Equal(t, []zapcore.Field{
+		zap.Int("http.status_code", 404),
+		zap.Duration("http.client.duration", time.Second),
+	}, HTTPResponse(404, time.Second, trace.SpanKindClient))
+}
+
+// TestStatusFromHTTPKind unit tests for span kind aware HTTP status.
+func TestStatusFromHTTPKind(t *testing.T) {
+	code, _ := StatusFromHTTPKind(http.StatusNotFound, trace.SpanKindServer)
+	assert.Equal(t, codes.Unset, code)
+	code, desc := StatusFromHTTPKind(http.StatusNotFound, trace.SpanKindClient)
+	assert.Equal(t, codes.Error, code)
+	assert.Equal(t, "Not Found", desc)
+
+	assert.Equal(t, trace.SpanKindServer, newOptions().kind())
+	assert.Equal(t, trace.SpanKindClient, newOptions(WithSpanKind(trace.SpanKindClient)).kind())
+}
+
+// fieldsMap encodes fields to a map.
+func fieldsMap(fields []zapcore.Field) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return enc.Fields
+}
@@ -0,0 +1,129 @@
+package otelzap
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// Interner interns attribute keys and small string values,
+// so the same strings converted millions of times (e.g. "user_id", "ok")
+// share memory and the prefixed keys (see WithLoggerNamespace) and
+// byte string values (see zap.ByteString) are not allocated every time.
+//
+// The interner is bounded: once full, new strings are not interned.
+// It is safe for concurrent use and can be shared by many loggers.
+type Interner struct {
+	mu     sync.RWMutex
+	size   int
+	maxLen int
+	strs   map[string]string
+}
+
+// NewInterner creates a new interner holding at most size strings
+// no longer than maxLen bytes.
+func NewInterner(size, maxLen int) *Interner {
+	return &Interner{
+		size:   size,
+		maxLen: maxLen,
+		strs:   make(map[string]string, size),
+	}
+}
+
+// WithInterner enables interning of attribute keys and small string values.
+// See Interner for details.
+func WithInterner(in *Interner) Option {
+	return func(o *options) {
+		o.interner = in
+	}
+}
+
+// String gets the interned copy of the string.
+func (in *Interner) String(s string) string {
+	if len(s) > in.maxLen {
+		return s // too long
+	}
+
+	in.mu.RLock()
+	is, ok := in.strs[s]
+	in.mu.RUnlock()
+	if ok {
+		return is
+	}
+
+	return in.add(s)
+}
+
+// bytes gets the interned string of bytes, allocated only once.
+func (in *Interner) bytes(b []byte) string {
+	if len(b) > in.maxLen {
+		return string(b) // too long
+	}
+
+	in.mu.RLock()
+	is, ok := in.strs[string(b)] // no allocation
+	in.mu.RUnlock()
+	if ok {
+		return is
+	}
+
+	return in.add(string(b))
+}
+
+// concat gets the interned concatenation of two strings.
+func (in *Interner) concat(a, b string) string {
+	if len(a)+len(b) > in.maxLen {
+		return a + b // too long
+	}
+
+	pbuf := bufferPool.Get().(*[]byte)
+	buf := append(append((*pbuf)[:0], a...), b...)
+	s := in.bytes(buf)
+	*pbuf = buf[:0]
+	bufferPool.Put(pbuf)
+	return s
+}
+
+// add adds the string to the interner, if not full.
+func (in *Interner) add(s string) string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if is, ok := in.strs[s]; ok {
+		return is // already added concurrently
+	}
+	if len(in.strs) < in.size {
+		in.strs[s] = s
+	}
+	return s
+}
+
+// internAttributes interns the keys and string values of attributes.
+func (in *Interner) internAttributes(attrs []attribute.KeyValue) {
+	for i, kv := range attrs {
+		attrs[i].Key = attribute.Key(in.String(string(kv.Key)))
+		if kv.Value.Type() == attribute.STRING {
+			if s := kv.Value.AsString(); len(s) <= in.maxLen {
+				attrs[i].Value = attribute.StringValue(in.String(s))
+			}
+		}
+	}
+}
+
+// appendByteString converts and appends a byte string field
+// without allocation of the interned value.
+func (in *Interner) appendByteString(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	return append(attributes, attribute.String(in.String(field.Key), in.bytes(field.Interface.([]byte))))
+}
+
+// prefixKeys prefixes all the attribute keys using the interner, if any.
+func (o *options) prefixKeys(attrs []attribute.KeyValue, prefix string) {
+	if o.interner == nil {
+		prefixKeys(attrs, prefix)
+		return
+	}
+
+	for i := range attrs {
+		attrs[i].Key = attribute.Key(o.interner.concat(prefix, string(attrs[i].Key)))
+	}
+}
@@ -0,0 +1,44 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestInterner unit tests for the interner.
+func TestInterner(t *testing.T) {
+	in := NewInterner(2, 4)
+	foo := in.String(string([]byte("foo")))
+	assert.Equal(t, "foo", foo)
+	b := []byte("foo")
+	assert.Zero(t, testing.AllocsPerRun(10, func() { foo = in.bytes(b) }))
+	assert.Equal(t, "foo", foo)
+	assert.Equal(t, "toolong", in.String("toolong"))
+	assert.Equal(t, "a.b", in.concat("a.", "b"))
+	assert.Len(t, in.strs, 2)
+	assert.Equal(t, "bar", in.String("bar")) // full, not interned
+	assert.Len(t, in.strs, 2)
+
+	o := newOptions(WithInterner(NewInterner(100, 16)))
+	attrs := o.appendZapFields(nil,
+		zap.ByteString("bs", []byte("ok")),
+		zap.String("str", "ok"),
+		zap.Int("int", 1))
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("bs", "ok"),
+		attribute.String("str", "ok"),
+		attribute.Int("int", 1),
+	}, attrs)
+	o.prefixKeys(attrs, "my.")
+	assert.Equal(t, attribute.Key("my.bs"), attrs[0].Key)
+
+	field := zap.ByteString("bs", []byte("ok"))
+	allocs := testing.AllocsPerRun(100, func() {
+		attrs = o.appendZapFields(attrs[:0], field)
+		o.prefixKeys(attrs, "my.")
+	})
+	assert.Zero(t, allocs)
+}
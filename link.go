@@ -0,0 +1,77 @@
+package otelzap
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Link creates a field with the span link: trace and span IDs
+// and the link attributes, e.g. in log output:
+//
+//	"link": {"trace_id": "...", "span_id": "...", "attributes": {...}}
+//
+// In span events the link is a JSON string of the same structure.
+func Link(key string, link trace.Link) zapcore.Field {
+	return zap.Object(key, linkObject(link))
+}
+
+// linkObject marshals the span link.
+type linkObject trace.Link
+
+// MarshalLogObject implements zapcore.ObjectMarshaler interface.
+func (l linkObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("trace_id", l.SpanContext.TraceID().String())
+	enc.AddString("span_id", l.SpanContext.SpanID().String())
+	if len(l.Attributes) != 0 {
+		return enc.AddObject("attributes", attributesObject(l.Attributes))
+	}
+	return nil
+}
+
+// attributesObject marshals the attributes.
+type attributesObject []attribute.KeyValue
+
+// MarshalLogObject implements zapcore.ObjectMarshaler interface.
+func (attrs attributesObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range attrs {
+		if err := enc.AddReflected(string(attr.Key), attr.Value.AsInterface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkAttribute converts the span link to a JSON string attribute.
+func linkAttribute(key string, link trace.Link) attribute.KeyValue {
+	obj := struct {
+		TraceID    string                 `json:"trace_id"`
+		SpanID     string                 `json:"span_id"`
+		Attributes map[string]interface{} `json:"attributes,omitempty"`
+	}{
+		TraceID: link.SpanContext.TraceID().String(),
+		SpanID:  link.SpanContext.SpanID().String(),
+	}
+	if len(link.Attributes) != 0 {
+		obj.Attributes = attributesMap(link.Attributes)
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil { // unlikely
+		return attribute.String(key, err.Error())
+	}
+	return attribute.String(key, string(b))
+}
+
+// spanContextAttribute converts the span context to a compact string
+// attribute in W3C "traceparent" format: version, trace ID, span ID and
+// trace flags (sampled or not), e.g. "00-0102...0f10-0102030405060708-01".
+func spanContextAttribute(key string, sc trace.SpanContext) attribute.KeyValue {
+	return attribute.String(key, "00-"+
+		sc.TraceID().String()+"-"+
+		sc.SpanID().String()+"-"+
+		sc.TraceFlags().String())
+}
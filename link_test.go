@@ -0,0 +1,55 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLink unit tests for span link field.
+func TestLink(t *testing.T) {
+	link := trace.Link{
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		}),
+		Attributes: []attribute.KeyValue{attribute.String("foo", "bar")},
+	}
+
+	expected := `{"trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708","attributes":{"foo":"bar"}}`
+	assert.Equal(t, []attribute.KeyValue{attribute.String("link", expected)},
+		appendZapField(nil, Link("link", link)))
+	assert.Equal(t, attribute.String("link", expected), Any("link", link))
+
+	// log output
+	enc := zapcore.NewMapObjectEncoder()
+	Link("link", link).AddTo(enc)
+	assert.Equal(t, map[string]interface{}{
+		"trace_id":   "0102030405060708090a0b0c0d0e0f10",
+		"span_id":    "0102030405060708",
+		"attributes": map[string]interface{}{"foo": "bar"},
+	}, enc.Fields["link"])
+
+	// no attributes
+	link.Attributes = nil
+	assert.Equal(t, attribute.String("link", `{"trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}`),
+		Any("link", link))
+	assert.Equal(t, zap.Object("link", linkObject(link)), Link("link", link))
+}
+
+// TestSpanContext unit tests for span context conversion.
+func TestSpanContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	assert.Equal(t, attribute.String("span", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01"), Any("span", sc))
+	assert.Equal(t, []attribute.KeyValue{attribute.String("span", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-00")},
+		appendZapField(nil, zap.Any("span", sc.WithTraceFlags(0))))
+}
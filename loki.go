@@ -0,0 +1,46 @@
+package otelzap
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Grafana Loki field names expected by derived fields defaults.
+const (
+	LokiTraceIDKey = "traceID"
+	LokiSpanIDKey  = "spanID"
+)
+
+// LokiLabels converts attributes into Loki label-safe key/value pairs.
+// Invalid label name characters are replaced with underscores.
+func LokiLabels(attrs []attribute.KeyValue) map[string]string {
+	labels := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		labels[lokiLabelName(string(attr.Key))] = attr.Value.Emit()
+	}
+	return labels
+}
+
+// lokiLabelName converts a key into label name matching `[a-zA-Z_][a-zA-Z0-9_]*`.
+func lokiLabelName(key string) string {
+	var sb strings.Builder
+	sb.Grow(len(key) + 1)
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestLokiLabels unit tests for LokiLabels function.
+func TestLokiLabels(t *testing.T) {
+	assert.Empty(t, LokiLabels(nil))
+	assert.Equal(t,
+		map[string]string{
+			"http_method": "GET",
+			"_1st":        "true",
+			"_":           "123",
+			"ok_":         "x",
+		},
+		LokiLabels([]attribute.KeyValue{
+			attribute.String("http.method", "GET"),
+			attribute.Bool("1st", true),
+			attribute.Int("", 123),
+			attribute.String("ok-", "x"),
+		}))
+}
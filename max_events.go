@@ -0,0 +1,48 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// eventsTruncatedEventName is the name of event written if events are truncated.
+const eventsTruncatedEventName = "log.events_truncated"
+
+// WithMaxEvents limits the number of events written to the span.
+// The entries exceeding the limit are counted and written as a single
+// "log.events_truncated" event on Sync, protecting exporters and backends
+// from pathological spans. Zero means unlimited.
+func WithMaxEvents(max int) Option {
+	return func(o *options) {
+		o.maxEvents = max
+	}
+}
+
+// allowEvent counts an event and checks if the limit is not exceeded.
+func (st *spanState) allowEvent(max int) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.events < max {
+		st.events++
+		return true
+	}
+
+	st.truncated++
+	return false
+}
+
+// flushTruncated writes the number of truncated events, if any.
+func (zs zapSpanCore) flushTruncated() {
+	st := zs.state
+	st.mu.Lock()
+	n := st.truncated
+	st.truncated = 0
+	st.mu.Unlock()
+
+	if n == 0 {
+		return // nothing truncated
+	}
+
+	zs.addEventAs(eventsTruncatedEventName,
+		[]attribute.KeyValue{suppressedCountKey.Int(n)}, nil, nil)
+}
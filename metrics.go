@@ -0,0 +1,109 @@
+package otelzap
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// Int64Counter is the counter instrument, e.g. OpenTelemetry syncint64.Counter.
+type Int64Counter interface {
+	Add(ctx context.Context, incr int64, attrs ...attribute.KeyValue)
+}
+
+// Float64Histogram is the histogram instrument, e.g. OpenTelemetry syncfloat64.Histogram.
+type Float64Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...attribute.KeyValue)
+}
+
+// metricHook derives a metric from the entries.
+type metricHook struct {
+	message   string
+	level     zapcore.Level
+	counter   Int64Counter
+	key       string // numeric field of the histogram
+	histogram Float64Histogram
+}
+
+// WithMetricCounter increments the counter on each entry
+// with the message and level, so metrics can be derived
+// from structured logs without a separate pipeline.
+// The measurements are recorded with the span's context,
+// so the OpenTelemetry SDK can attach the trace as an exemplar.
+func WithMetricCounter(message string, level zapcore.Level, counter Int64Counter) Option {
+	return func(o *options) {
+		o.metricHooks = append(o.metricHooks, metricHook{
+			message: message,
+			level:   level,
+			counter: counter,
+		})
+	}
+}
+
+// WithMetricHistogram records the numeric field (e.g. "latency") of each entry
+// with the message and level to the histogram. Durations are recorded in seconds.
+// The entries without the numeric field are ignored.
+// The measurements are recorded with the span's context,
+// so the OpenTelemetry SDK can attach the trace as an exemplar.
+func WithMetricHistogram(message string, level zapcore.Level, key string, histogram Float64Histogram) Option {
+	return func(o *options) {
+		o.metricHooks = append(o.metricHooks, metricHook{
+			message:   message,
+			level:     level,
+			key:       key,
+			histogram: histogram,
+		})
+	}
+}
+
+// recordMetrics records the metrics of the entry.
+func (zs zapSpanCore) recordMetrics(entry zapcore.Entry, fields []zapcore.Field) {
+	var ctx context.Context
+	for _, h := range zs.opts.metricHooks {
+		if h.message != entry.Message || h.level != entry.Level {
+			continue
+		}
+		if ctx == nil {
+			ctx = trace.ContextWithSpan(context.Background(), zs.span)
+		}
+
+		if h.counter != nil {
+			h.counter.Add(ctx, 1)
+		}
+		if h.histogram != nil {
+			if v, ok := numericField(h.key, fields); ok {
+				h.histogram.Record(ctx, v)
+			} else if v, ok := numericField(h.key, zs.with); ok {
+				h.histogram.Record(ctx, v)
+			}
+		}
+	}
+}
+
+// numericField gets the value of the last numeric field with the key.
+func numericField(key string, fields []zapcore.Field) (float64, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if f.Key != key {
+			continue
+		}
+
+		switch f.Type {
+		case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+			return float64(f.Integer), true
+		case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+			return float64(uint64(f.Integer)), true
+		case zapcore.Float64Type:
+			return math.Float64frombits(uint64(f.Integer)), true
+		case zapcore.Float32Type:
+			return float64(math.Float32frombits(uint32(f.Integer))), true
+		case zapcore.DurationType:
+			return durationIn(time.Duration(f.Integer), time.Second), true
+		}
+	}
+	return 0, false
+}
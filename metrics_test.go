@@ -0,0 +1,69 @@
+package otelzap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+// fakeCounter is a test Int64Counter.
+type fakeCounter struct {
+	total int64
+	spans []trace.SpanContext // of the measurement context
+}
+
+func (c *fakeCounter) Add(ctx context.Context, incr int64, _ ...attribute.KeyValue) {
+	c.total += incr
+	c.spans = append(c.spans, trace.SpanContextFromContext(ctx))
+}
+
+// fakeHistogram is a test Float64Histogram.
+type fakeHistogram struct {
+	values []float64
+	spans  []trace.SpanContext // of the measurement context
+}
+
+func (h *fakeHistogram) Record(ctx context.Context, value float64, _ ...attribute.KeyValue) {
+	h.values = append(h.values, value)
+	h.spans = append(h.spans, trace.SpanContextFromContext(ctx))
+}
+
+func TestSpanLoggerMetrics(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	})
+	span.EXPECT().SpanContext().Return(sc).AnyTimes()
+
+	counter, histogram := &fakeCounter{}, &fakeHistogram{}
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L,
+		WithMetricCounter("request failed", zapcore.ErrorLevel, counter),
+		WithMetricHistogram("request done", zapcore.InfoLevel, "latency", histogram))
+
+	span.EXPECT().
+		AddEvent(gomock.Any(), gomock.Any()).
+		Times(6)
+	SL.Error("request failed")
+	SL.Warn("request failed") // other level
+	SL.Error("request failed", zap.Int("code", 500))
+	SL.Info("request done", zap.Int("latency", 15))
+	SL.With(zap.Duration("latency", 1500*time.Millisecond)).Info("request done")
+	SL.Info("request done") // no latency
+
+	assert.Equal(t, int64(2), counter.total)
+	assert.Equal(t, []trace.SpanContext{sc, sc}, counter.spans)
+	assert.Equal(t, []float64{15, 1.5}, histogram.values)
+	assert.Equal(t, []trace.SpanContext{sc, sc}, histogram.spans) // for exemplars
+}
@@ -0,0 +1,73 @@
+package otelzap
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// loggerKey is the context key of the logger.
+type loggerKey struct{}
+
+// ContextWithLogger returns a copy of the context with the logger.
+func ContextWithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext gets the logger from the context.
+// If there is no logger, the global zap.L() logger is returned.
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok && logger != nil {
+		return logger
+	}
+	return zap.L()
+}
+
+// Middleware creates HTTP middleware which puts the span logger
+// bound to the request's span into the request context (see LoggerFromContext).
+// The span should be started by a previous middleware (e.g. otelhttp).
+func Middleware(logger *zap.Logger, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts...)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			span := trace.SpanFromContext(ctx)
+			log := SpanLogger(span, logger, o.debugOptions(ctx, opts)...)
+			if fields := o.contextFields(ctx); len(fields) != 0 {
+				log = log.With(fields...)
+			}
+
+			if header := o.correlationHeader; header != "" || o.requestIDGen != nil {
+				if header == "" {
+					header = DefaultRequestIDHeader
+				}
+				field, attr, ok := HTTPCorrelationID(r.Header, header)
+				if !ok && o.requestIDGen != nil {
+					id := o.requestIDGen()
+					w.Header().Set(header, id)
+					field, attr, ok = zap.String(CorrelationIDKey, id), attribute.String(CorrelationIDKey, id), true
+				}
+				if ok {
+					span.SetAttributes(attr)
+					log = log.With(field)
+				}
+			}
+
+			if !o.httpStatus {
+				next.ServeHTTP(w, r.WithContext(ContextWithLogger(ctx, log)))
+				return
+			}
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ContextWithLogger(ctx, log)))
+			if code, desc := StatusFromHTTPKind(rec.code(), o.kind()); code != codes.Unset {
+				span.SetStatus(code, desc)
+			}
+			logHTTPStatus(log, rec.code())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package otelzap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Same(t, zap.L(), LoggerFromContext(context.Background()))
+
+	L := zap.NewNop()
+	assert.Same(t, L, LoggerFromContext(ContextWithLogger(context.Background(), L)))
+}
+
+func TestMiddleware(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, buf := newJSONLogger()
+	handler := Middleware(L, WithCorrelationHeader("X-Request-ID"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			LoggerFromContext(r.Context()).Info("handled")
+		}))
+
+	span.EXPECT().
+		SetAttributes(attribute.String("correlation.id", "123"))
+	span.EXPECT().
+		AddEvent("handled",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("correlation.id", "123"),
+			))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "123")
+	r = r.WithContext(trace.ContextWithSpan(r.Context(), span))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, `{"level":"info","msg":"handled","correlation.id":"123"}`, buf.Stripped())
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	handler := Middleware(L, WithRequestIDGenerator(func() string { return "gen" }))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	span.EXPECT().
+		SetAttributes(attribute.String("correlation.id", "gen"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(trace.ContextWithSpan(r.Context(), span))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, "gen", w.Header().Get("X-Request-ID"))
+
+	// provided by client
+	span.EXPECT().
+		SetAttributes(attribute.String("correlation.id", "client"))
+	r.Header.Set("X-Request-ID", "client")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Empty(t, w.Header().Get("X-Request-ID"))
+}
+
+// tenantKey is the context key of tenant used in tests.
+type tenantKey struct{}
+
+// tenantFields extracts tenant from the context.
+func tenantFields(ctx context.Context) []zapcore.Field {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+		return []zapcore.Field{zap.String("tenant", tenant)}
+	}
+	return nil
+}
+
+func TestMiddlewareContextExtractor(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, buf := newJSONLogger()
+	handler := Middleware(L, WithContextExtractor(tenantFields))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			LoggerFromContext(r.Context()).Info("handled")
+		}))
+
+	span.EXPECT().
+		AddEvent("handled",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("tenant", "acme"),
+			))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := context.WithValue(r.Context(), tenantKey{}, "acme")
+	r = r.WithContext(trace.ContextWithSpan(ctx, span))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, `{"level":"info","msg":"handled","tenant":"acme"}`, buf.Stripped())
+}
+
+func TestSpanLoggerFromContextExtractor(t *testing.T) {
+	L, buf := newJSONLogger()
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	SpanLoggerFromContext(ctx, L, WithContextExtractor(tenantFields)).Info("no span")
+	SpanLoggerFromContext(context.Background(), L, WithContextExtractor(tenantFields)).Info("no tenant")
+
+	assert.Equal(t, `{"level":"info","msg":"no span","tenant":"acme"}`+"\n"+
+		`{"level":"info","msg":"no tenant"}`, buf.Stripped())
+}
+
+func TestMiddlewareHTTPStatus(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, buf := newJSONLogger()
+	handler := Middleware(L, WithHTTPStatus())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+			_, _ = w.Write([]byte("hello"))
+		}))
+
+	span.EXPECT().
+		AddEvent("HTTP request completed",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.Int("http.status_code", 200),
+			))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(trace.ContextWithSpan(r.Context(), span)))
+
+	span.EXPECT().
+		SetStatus(codes.Error, "Bad Gateway")
+	span.EXPECT().
+		AddEvent("HTTP request completed",
+			trace.WithAttributes(
+				attribute.String("zap.level", "error"),
+				attribute.Int("http.status_code", 502),
+			))
+	r = httptest.NewRequest(http.MethodGet, "/fail", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r.WithContext(trace.ContextWithSpan(r.Context(), span)))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	assert.Equal(t, `{"level":"info","msg":"HTTP request completed","http.status_code":200}`+"\n"+
+		`{"level":"error","msg":"HTTP request completed","http.status_code":502}`, buf.Stripped())
+}
+
+func TestMiddlewareHTTPStatusFlusher(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	handler := Middleware(L, WithHTTPStatus())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f, ok := w.(http.Flusher)
+			if assert.True(t, ok) {
+				_, _ = w.Write([]byte("data: hello\n\n"))
+				f.Flush()
+			}
+			_, ok = w.(http.Hijacker)
+			assert.True(t, ok)
+		}))
+
+	span.EXPECT().AddEvent("HTTP request completed", gomock.Any())
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r.WithContext(trace.ContextWithSpan(r.Context(), span)))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "data: hello\n\n", w.Body.String())
+}
@@ -0,0 +1,252 @@
+package otelzap
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// unsupportedCount is the number of values of unsupported types.
+var unsupportedCount int64
+
+// WithNoReflection disables reflection and JSON marshaling of field values,
+// for security- and performance-sensitive applications. The known types
+// (see Any), encoding.TextMarshaler, fmt.Stringer and ZAP array and object
+// marshalers are still converted, the unknown types are rendered as
+// "<unsupported T>" and counted (see UnsupportedCount).
+//
+// The conversion cache and struct flattening are not used in this mode.
+func WithNoReflection() Option {
+	return func(o *options) {
+		o.noReflection = true
+	}
+}
+
+// UnsupportedCount gets the number of values rendered as "<unsupported T>"
+// since the start of the process, see WithNoReflection.
+func UnsupportedCount() int64 {
+	return atomic.LoadInt64(&unsupportedCount)
+}
+
+// appendZapFieldNoReflect converts and appends a ZAP field without reflection.
+func appendZapFieldNoReflect(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	switch field.Type {
+	case zapcore.ReflectType, // see zap.Reflect()
+		zapcore.ArrayMarshalerType,  // see zap.Strings(), zap.Int64s(), ...
+		zapcore.ObjectMarshalerType: // see zap.Object()
+		return append(attributes, anyNoReflect(field.Key, field.Interface))
+	case zapcore.InlineMarshalerType: // see zap.Inline()
+		if obj, ok := field.Interface.(zapcore.ObjectMarshaler); ok {
+			return appendInline(attributes, obj, anyNoReflect)
+		}
+		return attributes // nothing to inline
+	}
+
+	return appendZapField(attributes, field)
+}
+
+// anyNoReflect converts value to OpenTelemetry attribute without reflection.
+func anyNoReflect(key string, value interface{}) attribute.KeyValue {
+	if kv, _, ok := knownAttribute(key, value); ok {
+		return kv
+	}
+
+	switch t := value.(type) {
+	case []interface{}: // see zapcore.MapObjectEncoder
+		return sliceNoReflect(key, t)
+	case zapcore.ArrayMarshaler:
+		enc := zapcore.NewMapObjectEncoder()
+		if err := enc.AddArray(key, t); err == nil {
+			if items, ok := enc.Fields[key].([]interface{}); ok {
+				return sliceNoReflect(key, items)
+			}
+			return attribute.StringSlice(key, nil) // empty
+		}
+	case zapcore.ObjectMarshaler:
+		if s, err := objectJSON(t); err == nil {
+			return attribute.String(key, s)
+		}
+	}
+
+	return unsupported(key, value)
+}
+
+// sliceNoReflect converts the array items to a slice attribute.
+// The items of mixed types are rendered as strings.
+func sliceNoReflect(key string, items []interface{}) attribute.KeyValue {
+	values := make([]attribute.Value, 0, len(items))
+	for _, item := range items {
+		kv, _, ok := knownAttribute("", item)
+		if !ok {
+			return unsupported(key, items)
+		}
+		values = append(values, kv.Value)
+	}
+	if len(values) == 0 {
+		return attribute.StringSlice(key, nil)
+	}
+
+	typ := values[0].Type()
+	for _, v := range values[1:] {
+		if v.Type() != typ {
+			typ = attribute.INVALID // mixed
+			break
+		}
+	}
+
+	switch typ {
+	case attribute.BOOL:
+		return attribute.BoolSlice(key, valuesAs(values, attribute.Value.AsBool))
+	case attribute.INT64:
+		return attribute.Int64Slice(key, valuesAs(values, attribute.Value.AsInt64))
+	case attribute.FLOAT64:
+		return attribute.Float64Slice(key, valuesAs(values, attribute.Value.AsFloat64))
+	case attribute.STRING:
+		return attribute.StringSlice(key, valuesAs(values, attribute.Value.AsString))
+	}
+	return attribute.StringSlice(key, valuesAs(values, attribute.Value.Emit))
+}
+
+// objectJSON encodes the object as JSON using the ZAP encoder.
+// The reflected values of the object are rendered as "<unsupported T>".
+func objectJSON(obj zapcore.ObjectMarshaler) (string, error) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zapcore.Field{zap.Inline(noReflectObject{obj})})
+	if err != nil {
+		return "", err
+	}
+	defer buf.Free()
+	return strings.TrimSuffix(buf.String(), zapcore.DefaultLineEnding), nil
+}
+
+// unsupported renders the value of unsupported type and counts it.
+func unsupported(key string, value interface{}) attribute.KeyValue {
+	return attribute.String(key, unsupportedValue(value))
+}
+
+// unsupportedValue renders the value of unsupported type and counts it.
+func unsupportedValue(value interface{}) string {
+	atomic.AddInt64(&unsupportedCount, 1)
+	return fmt.Sprintf("<unsupported %T>", value)
+}
+
+// noReflectObject marshals the object replacing reflected values.
+type noReflectObject struct {
+	zapcore.ObjectMarshaler
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler interface.
+func (o noReflectObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(noReflectEncoder{enc})
+}
+
+// noReflectArray marshals the array replacing reflected values.
+type noReflectArray struct {
+	zapcore.ArrayMarshaler
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler interface.
+func (a noReflectArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(noReflectArrayEncoder{enc})
+}
+
+// noReflectEncoder is the object encoder rendering
+// reflected values as "<unsupported T>".
+type noReflectEncoder struct {
+	zapcore.ObjectEncoder
+}
+
+// AddReflected adds the value of known type (see Any),
+// other values are rendered as unsupported.
+func (e noReflectEncoder) AddReflected(key string, value interface{}) error {
+	kv, _, ok := knownAttribute(key, value)
+	if !ok {
+		e.AddString(key, unsupportedValue(value))
+		return nil
+	}
+
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		e.AddBool(key, kv.Value.AsBool())
+	case attribute.INT64:
+		e.AddInt64(key, kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		e.AddFloat64(key, kv.Value.AsFloat64())
+	case attribute.STRING:
+		e.AddString(key, kv.Value.AsString())
+	default: // slices
+		return e.ObjectEncoder.AddArray(key, valueArray(kv.Value))
+	}
+	return nil
+}
+
+// valueArray marshals the slice attribute value.
+type valueArray attribute.Value
+
+// MarshalLogArray implements zapcore.ArrayMarshaler interface.
+func (v valueArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	switch value := attribute.Value(v); value.Type() {
+	case attribute.BOOLSLICE:
+		for _, b := range value.AsBoolSlice() {
+			enc.AppendBool(b)
+		}
+	case attribute.INT64SLICE:
+		for _, i := range value.AsInt64Slice() {
+			enc.AppendInt64(i)
+		}
+	case attribute.FLOAT64SLICE:
+		for _, f := range value.AsFloat64Slice() {
+			enc.AppendFloat64(f)
+		}
+	case attribute.STRINGSLICE:
+		for _, s := range value.AsStringSlice() {
+			enc.AppendString(s)
+		}
+	}
+	return nil
+}
+
+// AddObject adds the nested object replacing reflected values.
+func (e noReflectEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	return e.ObjectEncoder.AddObject(key, noReflectObject{obj})
+}
+
+// AddArray adds the nested array replacing reflected values.
+func (e noReflectEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	return e.ObjectEncoder.AddArray(key, noReflectArray{arr})
+}
+
+// noReflectArrayEncoder is the array encoder rendering
+// reflected values as "<unsupported T>".
+type noReflectArrayEncoder struct {
+	zapcore.ArrayEncoder
+}
+
+// AppendReflected renders the value as unsupported.
+func (e noReflectArrayEncoder) AppendReflected(value interface{}) error {
+	e.AppendString(unsupportedValue(value))
+	return nil
+}
+
+// AppendObject appends the nested object replacing reflected values.
+func (e noReflectArrayEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(noReflectObject{obj})
+}
+
+// AppendArray appends the nested array replacing reflected values.
+func (e noReflectArrayEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(noReflectArray{arr})
+}
+
+// valuesAs converts attribute values to slice.
+func valuesAs[T any](values []attribute.Value, as func(attribute.Value) T) []T {
+	out := make([]T, 0, len(values))
+	for _, v := range values {
+		out = append(out, as(v))
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package otelzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestNoReflection unit tests for conversion without reflection.
+func TestNoReflection(t *testing.T) {
+	type Request struct {
+		ID string `json:"id"`
+	}
+
+	o := newOptions(WithNoReflection(), WithFlattenStructs())
+	u := user{Name: "foo", Age: 42, Roles: []string{"a"}}
+	before := UnsupportedCount()
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int64("int", 1),
+		attribute.StringSlice("strs", []string{"a", "b"}),
+		attribute.Int64Slice("ints", []int64{1, 2}),
+		attribute.BoolSlice("bools", []bool{true}),
+		attribute.Float64Slice("floats", []float64{1.5}),
+		attribute.StringSlice("durs", []string{"1s"}),
+		attribute.StringSlice("empty", nil),
+		attribute.StringSlice("refl", []string{"a"}),
+		attribute.String("obj", `{"name":"foo","age":42,"admin":false,"roles":["a"]}`),
+		attribute.Bool("admin", false),
+		attribute.Int("age", 42),
+		attribute.String("name", "foo"),
+		attribute.StringSlice("roles", []string{"a"}),
+		attribute.String("req", "<unsupported otelzap.Request>"),
+		attribute.String("map", "<unsupported map[string]int>"),
+	}, o.appendZapFields(nil,
+		zap.Int("int", 1),
+		zap.Strings("strs", []string{"a", "b"}),
+		zap.Ints("ints", []int{1, 2}),
+		zap.Bools("bools", []bool{true}),
+		zap.Float64s("floats", []float64{1.5}),
+		zap.Durations("durs", []time.Duration{time.Second}),
+		zap.Strings("empty", nil),
+		zap.Reflect("refl", []string{"a"}),
+		zap.Object("obj", u),
+		zap.Inline(u),
+		zap.Any("req", Request{ID: "foo"}),
+		zap.Any("map", map[string]int{"a": 1}),
+	))
+	assert.Equal(t, before+2, UnsupportedCount())
+
+	// reflected values inside the object marshalers
+	before = UnsupportedCount()
+	assert.Equal(t,
+		attribute.String("obj", `{"req":"<unsupported otelzap.Request>","ids":[1,2],"nested":{"items":["<unsupported otelzap.Request>"]}}`),
+		anyNoReflect("obj", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			if err := enc.AddReflected("req", Request{ID: "foo"}); err != nil {
+				return err
+			}
+			if err := enc.AddReflected("ids", []int{1, 2}); err != nil {
+				return err
+			}
+			return enc.AddObject("nested", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				return enc.AddArray("items", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+					return enc.AppendReflected(Request{ID: "bar"})
+				}))
+			}))
+		})))
+	assert.Equal(t, before+2, UnsupportedCount())
+
+	assert.Equal(t, attribute.StringSlice("mixed", []string{"a", "1"}),
+		anyNoReflect("mixed", []interface{}{"a", 1}))
+}
@@ -0,0 +1,45 @@
+package otelzap
+
+import (
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithOmitZero drops attributes having zero value
+// ("", 0, false or empty slice), trimming the noise from events
+// generated by loosely populated structs.
+func WithOmitZero() Option {
+	return func(o *options) {
+		o.omitZero = true
+	}
+}
+
+// omitZero removes zero value attributes starting from the index.
+func omitZero(attrs []attribute.KeyValue, from int) []attribute.KeyValue {
+	out := attrs[:from]
+	for _, attr := range attrs[from:] {
+		if !isZeroAttribute(attr.Value) {
+			out = append(out, attr)
+		}
+	}
+	return out
+}
+
+// isZeroAttribute checks if attribute value is zero.
+func isZeroAttribute(v attribute.Value) bool {
+	switch v.Type() {
+	case attribute.INVALID:
+		return true
+	case attribute.BOOL:
+		return !v.AsBool()
+	case attribute.INT64:
+		return v.AsInt64() == 0
+	case attribute.FLOAT64:
+		return v.AsFloat64() == 0
+	case attribute.STRING:
+		return v.AsString() == ""
+	default: // slices
+		return reflect.ValueOf(v.AsInterface()).Len() == 0
+	}
+}
@@ -0,0 +1,41 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestOmitZero unit tests for zero value attributes omission.
+func TestOmitZero(t *testing.T) {
+	type User struct {
+		Name  string `otel:"name"`
+		Email string `otel:"email"`
+		Age   int    `otel:"age"`
+	}
+
+	o := newOptions(WithOmitZero(), WithFlattenStructs())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int("int", 1),
+		attribute.Bool("true", true),
+		attribute.StringSlice("strs", []string{"a"}),
+		attribute.String("user.name", "foo"),
+	}, o.appendZapFields(nil,
+		zap.String("empty", ""),
+		zap.Int("zero", 0),
+		zap.Int("int", 1),
+		zap.Bool("false", false),
+		zap.Bool("true", true),
+		zap.Float64("float", 0),
+		zap.Strings("no strs", nil),
+		zap.Strings("strs", []string{"a"}),
+		zap.Any("user", User{Name: "foo"}),
+	))
+
+	// disabled
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("empty", ""),
+	}, newOptions().appendZapFields(nil, zap.String("empty", "")))
+}
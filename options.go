@@ -0,0 +1,492 @@
+package otelzap
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldOrder defines the order of event attributes.
+type FieldOrder int
+
+// Known field orders.
+const (
+	// FieldOrderExtraFirst puts extra attributes (e.g. "zap.level") first,
+	// then With() fields, then call-site fields. This is the default.
+	FieldOrderExtraFirst FieldOrder = iota
+
+	// FieldOrderCallSiteFirst puts call-site fields first,
+	// then With() fields, then extra attributes.
+	FieldOrderCallSiteFirst
+
+	// FieldOrderExtraLast puts With() fields first,
+	// then call-site fields, then extra attributes.
+	FieldOrderExtraLast
+)
+
+// Option configures the span logger.
+type Option func(*options)
+
+// options contains the span logger configuration.
+type options struct {
+	parentSpanID bool // add "parent.span_id" attribute
+	jsonFields   bool // pack all fields into "log.fields"
+
+	maxEventBytes int            // per-event byte budget, 0 means unlimited
+	overflow      OverflowPolicy // what to do if budget exceeded
+
+	errorAsException bool // map "error" field to exception.*
+	exceptionAttrs   bool // map all error fields to exception.*
+	errorRename      bool // drop original "error" attribute
+
+	keyRenames map[string]string // field key -> attribute key
+	fieldOrder FieldOrder        // order of attributes
+	stackMode  StackMode         // how to add stack traces
+
+	sampleFirst      int // events per message always written
+	sampleThereafter int // then every Mth event is written
+
+	summary     bool // write "log.summary" event on Sync
+	errorCount  bool // set "log.error_count" span attribute
+	warnings    bool // set "log.has_warnings" span attribute
+	errStatus   bool // set span status on Error+ entries
+	entryTime   bool // use entry time as event timestamp
+	callerAttrs bool // add code.* attributes of the caller
+
+	firstError     bool     // capture the first error as span attributes
+	firstErrorKeys []string // key attributes of the first error
+
+	stackFrames       int      // max stack frames, 0 means unlimited
+	stackSkipRuntime  bool     // omit Go runtime frames
+	stackTrimPrefixes []string // file path prefixes to strip
+	stackElide        []string // function prefixes to omit
+
+	sourceRoots []string // source roots to strip from file paths
+
+	rawHeaderCase bool        // keep original casing of HTTP header names
+	trailer       http.Header // HTTP trailers to include
+
+	prettyJSON bool // indent JSON payloads
+
+	hashKeys map[string]bool   // keys of values to replace by hash
+	units    map[string]string // units of attribute keys
+
+	enums map[string]map[string]string // allowed values by key, lowercased
+
+	durationUnit time.Duration // numeric durations unit, 0 for strings
+	compactFlags bool          // collect boolean-true fields into "flags"
+
+	recordError        bool // call span.RecordError for error fields
+	recordErrorReplace bool // omit recorded error fields from events
+
+	metricHooks []metricHook // metrics derived from entries
+
+	tailBuffer int // size of recent entries buffer, 0 means disabled
+
+	throttle map[attribute.Key]int // throttled keys
+
+	deterministic bool // stable output for snapshot tests
+
+	correlationHeader string        // correlation ID header used by middleware
+	requestIDGen      func() string // generates missing request IDs
+
+	profile Profile // names of injected log fields
+
+	loggerNamespace bool // prefix keys with logger name
+	loggerScope     bool // add "log.logger" and "log.logger.leaf"
+
+	cache *ConversionCache // converted values cache, nil means disabled
+	debug *debugWriter     // prints events, nil means disabled
+
+	dedupWindow time.Duration // collapse repeated events, 0 means disabled
+
+	flattenStructs bool // struct fields as separate attributes
+	omitZero       bool // drop zero value attributes
+
+	maxEvents int // max events per span, 0 means unlimited
+
+	normalizeName    func(string) string // event name from message, nil means as is
+	messageTemplates bool                // message is a template of event name
+
+	contextExtractor func(context.Context) []zapcore.Field // app-specific context values
+
+	tenantKey    attribute.Key // required tenant attribute, empty means disabled
+	tenantPolicy TenantPolicy  // what to do if tenant is missing
+
+	levelAttrs []levelAttributes // static attributes per level
+
+	eventFilter func(zapcore.Entry, []zapcore.Field) bool // false means skip the span
+	spanLevel   zapcore.LevelEnabler                      // span events threshold
+	debugKey    string                                    // debug trigger member
+	debugValue  string                                    // debug trigger value
+
+	addEventTimeout time.Duration // AddEvent timeout, 0 means no timeout
+
+	asyncQueue int // async queue size, 0 means synchronous
+
+	httpStatus bool // middleware sets span status and logs the response status
+
+	spanKind trace.SpanKind // client or server, server by default
+
+	repanic bool // re-panic after recovery
+
+	securityKeys     map[string]SecuritySeverity // security-relevant field keys
+	securityMessages map[string]SecuritySeverity // security-relevant messages
+
+	provenance bool // add "<key>.src" companion attributes
+
+	export *eventExporter // writes events as JSON lines, nil means disabled
+
+	noReflection bool // never use reflection or JSON marshaling
+
+	interner *Interner // interns keys and small values, nil means disabled
+}
+
+// levelAttributes are static attributes of the level and above.
+type levelAttributes struct {
+	level zapcore.Level
+	attrs []attribute.KeyValue
+}
+
+// defaultOptions are used when no options provided.
+var defaultOptions = newOptions()
+
+// newOptions creates options from a list of Option.
+func newOptions(opts ...Option) *options {
+	o := &options{stackMode: StackEntry}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithParentSpanID adds "parent.span_id" attribute to each event.
+// The parent span ID is only known if the span implements `Parent() trace.SpanContext`
+// (as OpenTelemetry SDK spans do), otherwise the attribute is omitted.
+func WithParentSpanID() Option {
+	return func(o *options) {
+		o.parentSpanID = true
+	}
+}
+
+// WithJSONFields packs all converted fields into a single "log.fields"
+// attribute containing a JSON object. The "zap.*" attributes are kept as is.
+// This is useful for backends with low per-event attribute limits.
+func WithJSONFields() Option {
+	return func(o *options) {
+		o.jsonFields = true
+	}
+}
+
+// WithMaxEventBytes limits the overall size of event attributes
+// (sum of keys and values sizes) to keep events under collector/exporter
+// message size limits. The policy defines what to do if limit is exceeded.
+func WithMaxEventBytes(max int, policy OverflowPolicy) Option {
+	return func(o *options) {
+		o.maxEventBytes = max
+		o.overflow = policy
+	}
+}
+
+// WithErrorAsException maps the standard "error" field (see zap.Error)
+// to "exception.message" and "exception.type" semantic convention attributes.
+// If rename is true the original "error" attribute is omitted.
+func WithErrorAsException(rename bool) Option {
+	return func(o *options) {
+		o.errorAsException = true
+		o.errorRename = rename
+	}
+}
+
+// WithExceptionAttributes converts all error fields (see zap.Error
+// and zap.NamedError) to "exception.type", "exception.message" and,
+// when available, "exception.stacktrace" semantic convention attributes
+// instead of a single opaque string.
+func WithExceptionAttributes() Option {
+	return func(o *options) {
+		o.exceptionAttrs = true
+	}
+}
+
+// WithKeyRenames renames field keys during conversion, so legacy
+// field names (e.g. "reqId") can be normalized to standard attribute names
+// (e.g. "request.id") without touching the call sites.
+func WithKeyRenames(renames map[string]string) Option {
+	return func(o *options) {
+		if o.keyRenames == nil {
+			o.keyRenames = make(map[string]string, len(renames))
+		}
+		for from, to := range renames {
+			o.keyRenames[from] = to
+		}
+	}
+}
+
+// WithFieldOrder changes the order of event attributes.
+// Some backends display only the first N attributes, so
+// it is useful to put the most specific ones first.
+func WithFieldOrder(order FieldOrder) Option {
+	return func(o *options) {
+		o.fieldOrder = order
+	}
+}
+
+// WithStackTrace adds stack traces to events: the entry stack
+// (see zap.AddStacktrace) and/or the stack of an error field
+// having `StackTrace()` method (see pkg/errors).
+// By default only the entry stack is added (see StackEntry).
+func WithStackTrace(mode StackMode) Option {
+	return func(o *options) {
+		o.stackMode = mode
+	}
+}
+
+// WithEventSampling enables span event sampling independent of the zap sampler.
+// The first events per message are always written, then every Mth event
+// is written (none if thereafter is zero). The number of suppressed events
+// is written as "log.sampling" summary event on Sync.
+func WithEventSampling(first, thereafter int) Option {
+	return func(o *options) {
+		o.sampleFirst = first
+		o.sampleThereafter = thereafter
+	}
+}
+
+// WithSummary counts entries per level and writes them as
+// a single "log.summary" event (e.g. info=42, warn=3, error=1) on Sync.
+// This is a cheap way to see log volume per span.
+func WithSummary() Option {
+	return func(o *options) {
+		o.summary = true
+	}
+}
+
+// WithErrorCount counts Error (and above) entries and sets
+// the "log.error_count" span attribute on each error, so traces
+// can be filtered by the number of logged errors.
+func WithErrorCount() Option {
+	return func(o *options) {
+		o.errorCount = true
+	}
+}
+
+// WithWarningsFlag sets the "log.has_warnings=true" span attribute
+// once any Warn+ entry is written, so spans with warnings can be found
+// without storing every warning as an event.
+func WithWarningsFlag() Option {
+	return func(o *options) {
+		o.warnings = true
+	}
+}
+
+// WithErrorStatus sets the span status to codes.Error with the log message
+// as description once an Error (or above) entry is written,
+// so dashboards can tell the span failed. The latest error wins.
+func WithErrorStatus() Option {
+	return func(o *options) {
+		o.errStatus = true
+	}
+}
+
+// WithEntryTime uses the entry time as the span event timestamp
+// instead of the time the event is added, so events line up correctly
+// when logs are buffered (see WithAsync) or replayed.
+func WithEntryTime() Option {
+	return func(o *options) {
+		o.entryTime = true
+	}
+}
+
+// WithTailBuffer enables tail buffering: entries below Error level
+// are kept in a small ring buffer and are only written as events
+// if an Error entry occurs later. This gives full context of failures
+// without bloating the healthy spans.
+func WithTailBuffer(size int) Option {
+	return func(o *options) {
+		o.tailBuffer = size
+	}
+}
+
+// WithKeyThrottling marks keys as high-volume (e.g. "progress", "offset"),
+// so their values are only attached to every Nth event or,
+// if N is not positive, only when the value is changed.
+// This reduces attribute churn on long-running spans.
+func WithKeyThrottling(every int, keys ...string) Option {
+	return func(o *options) {
+		if o.throttle == nil {
+			o.throttle = make(map[attribute.Key]int, len(keys))
+		}
+		for _, key := range keys {
+			o.throttle[attribute.Key(key)] = every
+		}
+	}
+}
+
+// WithDeterministic enables the deterministic mode for tests:
+// time values are formatted in UTC and attributes are sorted by key.
+// This makes it feasible to snapshot-test the logging output.
+func WithDeterministic() Option {
+	return func(o *options) {
+		o.deterministic = true
+	}
+}
+
+// WithCorrelationHeader makes Middleware read the correlation ID
+// from the HTTP header (e.g. "X-Request-ID") and attach it to both
+// the span (as attribute) and the logger (as field).
+func WithCorrelationHeader(header string) Option {
+	return func(o *options) {
+		o.correlationHeader = header
+	}
+}
+
+// WithRequestIDGenerator makes Middleware generate a request ID if the request
+// has no correlation header (see WithCorrelationHeader, "X-Request-ID" by default).
+// The generated ID is attached to the span, the logger and the response header.
+// If gen is nil then NewRequestID is used.
+func WithRequestIDGenerator(gen func() string) Option {
+	return func(o *options) {
+		if gen == nil {
+			gen = NewRequestID
+		}
+		o.requestIDGen = gen
+	}
+}
+
+// WithTraceFields adds trace and span IDs to the log output as fields
+// with the given names, so logs can be correlated with traces.
+// An empty name disables the corresponding field.
+// See also WithProfile for predefined field names.
+func WithTraceFields(traceKey, spanKey string) Option {
+	return func(o *options) {
+		o.profile = Profile{
+			TraceIDKey: traceKey,
+			SpanIDKey:  spanKey,
+		}
+	}
+}
+
+// WithLokiFields adds trace and span IDs to the log output as
+// "traceID" and "spanID" fields, exactly as Grafana's Loki to Tempo
+// derived fields defaults expect. Same as WithProfile("grafana").
+func WithLokiFields() Option {
+	return WithProfile("grafana")
+}
+
+// traceFields gets the log fields of the span context.
+func (o *options) traceFields(span trace.Span) []zapcore.Field {
+	p := o.profile
+	if p.TraceIDKey == "" && p.SpanIDKey == "" && p.SampledKey == "" {
+		return nil // disabled
+	}
+
+	return p.Fields(span.SpanContext())
+}
+
+// WithContextExtractor sets the function used by SpanLoggerFromContext
+// and Middleware to get app-specific values (e.g. tenant, user, locale)
+// from the context as fields, attached to both log output and span events.
+func WithContextExtractor(extract func(ctx context.Context) []zapcore.Field) Option {
+	return func(o *options) {
+		o.contextExtractor = extract
+	}
+}
+
+// contextFields gets the log fields of the context.
+func (o *options) contextFields(ctx context.Context) []zapcore.Field {
+	var fields []zapcore.Field
+	if o.contextExtractor != nil {
+		fields = o.contextExtractor(ctx)
+	}
+
+	return o.appendTenantField(ctx, fields)
+}
+
+// WithLevelAttributes adds static attributes to events of the level
+// and above, e.g. "alert=true" on Error and above, so alerting pipelines
+// keyed on span events can be triggered without parsing messages.
+// Can be used multiple times for different levels.
+func WithLevelAttributes(level zapcore.Level, attrs ...attribute.KeyValue) Option {
+	return func(o *options) {
+		o.levelAttrs = append(o.levelAttrs, levelAttributes{
+			level: level,
+			attrs: attrs,
+		})
+	}
+}
+
+// appendLevelAttributes appends the static attributes of the level.
+func (o *options) appendLevelAttributes(attrs []attribute.KeyValue, level zapcore.Level) []attribute.KeyValue {
+	for _, la := range o.levelAttrs {
+		if level >= la.level {
+			attrs = append(attrs, la.attrs...)
+		}
+	}
+	return attrs
+}
+
+// WithEventFilter sets the predicate which decides (by the entry and
+// the call-site fields) whether the entry is written to the span.
+// This allows to suppress known-noisy messages (e.g. health checks,
+// heartbeats) from reaching the span while keeping them in the log output.
+func WithEventFilter(filter func(zapcore.Entry, []zapcore.Field) bool) Option {
+	return func(o *options) {
+		o.eventFilter = filter
+	}
+}
+
+// WithSpanLevel sets the level threshold of span events independently
+// of the wrapped core, e.g. keep the console at Info but write only
+// Warn and above to the span (or the reverse).
+// By default span events mirror the wrapped core's level.
+func WithSpanLevel(level zapcore.LevelEnabler) Option {
+	return func(o *options) {
+		o.spanLevel = level
+	}
+}
+
+// spanLevelOr gets the span events threshold, the core by default.
+func (o *options) spanLevelOr(core zapcore.LevelEnabler) zapcore.LevelEnabler {
+	if o.spanLevel != nil {
+		return o.spanLevel
+	}
+	return core
+}
+
+// WithLoggerNamespace prefixes converted field keys with the logger name
+// (e.g. "payments.order_id"), making it obvious which component attached
+// which attributes when multiple libraries log onto the same span.
+func WithLoggerNamespace() Option {
+	return func(o *options) {
+		o.loggerNamespace = true
+	}
+}
+
+// WithLoggerScope adds the full dotted logger name (e.g. "svc.handler.db")
+// as "log.logger" attribute and its leaf (e.g. "db") as "log.logger.leaf"
+// attribute, so backends can group by component at different granularities.
+func WithLoggerScope() Option {
+	return func(o *options) {
+		o.loggerScope = true
+	}
+}
+
+// spanAttributes gets the static attributes of the span
+// that should be added to each event.
+func (o *options) spanAttributes(span trace.Span) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+
+	if o.parentSpanID {
+		if ps, ok := span.(interface{ Parent() trace.SpanContext }); ok {
+			if parent := ps.Parent(); parent.HasSpanID() {
+				attrs = append(attrs, attribute.String("parent.span_id", parent.SpanID().String()))
+			}
+		}
+	}
+
+	return attrs
+}
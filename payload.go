@@ -0,0 +1,88 @@
+package otelzap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// hashPrefix is the prefix of hashed values.
+const hashPrefix = "sha256:"
+
+// lengthSuffix is the key suffix of hashed value length.
+const lengthSuffix = ".length"
+
+// WithPrettyJSON makes Payload render JSON bodies indented
+// instead of compact.
+func WithPrettyJSON() Option {
+	return func(o *options) {
+		o.prettyJSON = true
+	}
+}
+
+// Payload renders the request or response body (or any payload field)
+// according to its content type:
+//   - JSON is compacted (or indented, see WithPrettyJSON);
+//   - form-urlencoded is parsed into "<key>.<param>" fields;
+//   - everything else (and malformed JSON or forms) is replaced with
+//     "sha256:<hex>" hash and "<key>.length" fields, see WithHashKeys.
+func Payload(key, contentType string, body []byte, opts ...Option) []zapcore.Field {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var buf bytes.Buffer
+		var err error
+		if newOptions(opts...).prettyJSON {
+			err = json.Indent(&buf, body, "", "  ")
+		} else {
+			err = json.Compact(&buf, body)
+		}
+		if err == nil {
+			return []zapcore.Field{zap.String(key, buf.String())}
+		}
+
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return formFields(key, values)
+		}
+	}
+
+	return hashFields(key, body)
+}
+
+// formFields converts the form values into fields sorted by name.
+func formFields(key string, values url.Values) []zapcore.Field {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]zapcore.Field, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, zap.Strings(key+"."+name, values[name]))
+	}
+	return fields
+}
+
+// hashFields replaces the value with its hash and length.
+func hashFields(key string, data []byte) []zapcore.Field {
+	return []zapcore.Field{
+		zap.String(key, hashValue(data)),
+		zap.Int(key+lengthSuffix, len(data)),
+	}
+}
+
+// hashValue gets the "sha256:<hex>" hash of the data.
+func hashValue(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
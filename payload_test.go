@@ -0,0 +1,46 @@
+package otelzap_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestPayload(t *testing.T) {
+	body := []byte("{\n  \"id\": 1,\n  \"tags\": [\"a\"]\n}")
+	assert.Equal(t, []zapcore.Field{zap.String("body", `{"id":1,"tags":["a"]}`)},
+		Payload("body", "application/json; charset=utf-8", body))
+	assert.Equal(t, []zapcore.Field{zap.String("body", `{"id":1,"tags":["a"]}`)},
+		Payload("body", "application/problem+json", body))
+	assert.Equal(t, []zapcore.Field{zap.String("body", "{\n  \"id\": 1,\n  \"tags\": [\n    \"a\"\n  ]\n}")},
+		Payload("body", "application/json", body, WithPrettyJSON()))
+
+	assert.Equal(t, []zapcore.Field{
+		zap.Strings("body.a", []string{"1", "2"}),
+		zap.Strings("body.b", []string{"x y"}),
+	}, Payload("body", "application/x-www-form-urlencoded", []byte("b=x+y&a=1&a=2")))
+
+	hashed := []zapcore.Field{
+		zap.String("body", "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+		zap.Int("body.length", 5),
+	}
+	assert.Equal(t, hashed, Payload("body", "text/plain", []byte("hello")))
+	assert.Equal(t, hashed, Payload("body", "", []byte("hello")))
+	assert.Equal(t, hashed, Payload("body", "application/json", []byte("hello"))) // malformed
+	assert.Equal(t, hashFields("body", "%zz"), Payload("body", "application/x-www-form-urlencoded", []byte("%zz")))
+}
+
+// hashFields gets the expected hash fields of the value.
+func hashFields(key, value string) []zapcore.Field {
+	sum := sha256.Sum256([]byte(value))
+	return []zapcore.Field{
+		zap.String(key, "sha256:"+hex.EncodeToString(sum[:])),
+		zap.Int(key+".length", len(value)),
+	}
+}
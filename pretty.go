@@ -0,0 +1,52 @@
+package otelzap
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Sprint formats attributes in human-readable form,
+// one attribute per line with aligned values, e.g.:
+//
+//	zap.level = "info"
+//	foo       = 123
+//	bar       = [a b]
+//
+// Useful in debug output and test failure messages.
+func Sprint(attrs []attribute.KeyValue) string {
+	return string(appendPretty(nil, attrs, ""))
+}
+
+// appendPretty appends aligned attributes, each line is prefixed with indent.
+// There is no new line after the last attribute.
+func appendPretty(buf []byte, attrs []attribute.KeyValue, indent string) []byte {
+	width := 0
+	for _, kv := range attrs {
+		if len(kv.Key) > width {
+			width = len(kv.Key)
+		}
+	}
+
+	for i, kv := range attrs {
+		if i > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, indent...)
+		buf = append(buf, kv.Key...)
+		for n := len(kv.Key); n < width; n++ {
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, " = "...)
+		buf = appendValue(buf, kv.Value)
+	}
+	return buf
+}
+
+// appendValue appends a human-readable attribute value, strings are quoted.
+func appendValue(buf []byte, value attribute.Value) []byte {
+	if value.Type() == attribute.STRING {
+		return strconv.AppendQuote(buf, value.AsString())
+	}
+	return append(buf, value.Emit()...)
+}
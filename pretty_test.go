@@ -0,0 +1,25 @@
+package otelzap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestSprint(t *testing.T) {
+	assert.Equal(t, "", Sprint(nil))
+	assert.Equal(t, `foo = "bar"`, Sprint([]attribute.KeyValue{attribute.String("foo", "bar")}))
+	assert.Equal(t, `zap.level = "info"`+"\n"+
+		`foo       = 123`+"\n"+
+		`ok        = true`+"\n"+
+		`bar       = [a b]`,
+		Sprint([]attribute.KeyValue{
+			attribute.String("zap.level", "info"),
+			attribute.Int("foo", 123),
+			attribute.Bool("ok", true),
+			attribute.StringSlice("bar", []string{"a", "b"}),
+		}))
+}
@@ -0,0 +1,160 @@
+package otelzap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Profile defines the names and formats of log fields
+// injected to correlate logs with traces.
+type Profile struct {
+	TraceIDKey string // trace ID field name, empty to omit
+	SpanIDKey  string // span ID field name, empty to omit
+	SampledKey string // sampled flag field name, empty to omit
+
+	// FormatTraceID formats the trace ID, hex string by default.
+	FormatTraceID func(trace.TraceID) string
+
+	// FormatSpanID formats the span ID, hex string by default.
+	FormatSpanID func(trace.SpanID) string
+}
+
+// Fields gets the log fields of the span context.
+func (p Profile) Fields(sc trace.SpanContext) []zapcore.Field {
+	fields := make([]zapcore.Field, 0, 3)
+	if p.TraceIDKey != "" && sc.HasTraceID() {
+		if p.FormatTraceID != nil {
+			fields = append(fields, zap.String(p.TraceIDKey, p.FormatTraceID(sc.TraceID())))
+		} else {
+			fields = append(fields, zap.String(p.TraceIDKey, sc.TraceID().String()))
+		}
+	}
+	if p.SpanIDKey != "" && sc.HasSpanID() {
+		if p.FormatSpanID != nil {
+			fields = append(fields, zap.String(p.SpanIDKey, p.FormatSpanID(sc.SpanID())))
+		} else {
+			fields = append(fields, zap.String(p.SpanIDKey, sc.SpanID().String()))
+		}
+	}
+	if p.SampledKey != "" && sc.IsValid() {
+		fields = append(fields, zap.Bool(p.SampledKey, sc.IsSampled()))
+	}
+	return fields
+}
+
+// profiles contains all registered profiles.
+var profiles = struct {
+	sync.RWMutex
+	byName map[string]Profile
+}{
+	byName: map[string]Profile{
+		"otel": {
+			TraceIDKey: "trace_id",
+			SpanIDKey:  "span_id",
+		},
+		"ecs": {
+			TraceIDKey: "trace.id",
+			SpanIDKey:  "span.id",
+		},
+		"datadog": {
+			TraceIDKey: "dd.trace_id",
+			SpanIDKey:  "dd.span_id",
+			FormatTraceID: func(id trace.TraceID) string {
+				return strconv.FormatUint(binary.BigEndian.Uint64(id[8:]), 10)
+			},
+			FormatSpanID: func(id trace.SpanID) string {
+				return strconv.FormatUint(binary.BigEndian.Uint64(id[:]), 10)
+			},
+		},
+		"grafana": {
+			TraceIDKey: LokiTraceIDKey,
+			SpanIDKey:  LokiSpanIDKey,
+		},
+		"gcp": gcpEnvProfile(),
+	},
+}
+
+// RegisterProfile registers a custom profile, so it can be used by name
+// (see WithProfile). The existing profile with the same name is replaced.
+func RegisterProfile(name string, profile Profile) {
+	profiles.Lock()
+	defer profiles.Unlock()
+	profiles.byName[name] = profile
+}
+
+// LookupProfile gets the registered profile by name.
+// Built-in profiles are "otel", "ecs", "datadog", "grafana" and "gcp".
+// The "gcp" profile reads the project ID from the GOOGLE_CLOUD_PROJECT
+// environment variable, the bare trace ID is used if it is not set
+// (see GCPProfile to set the project ID explicitly).
+func LookupProfile(name string) (Profile, bool) {
+	profiles.RLock()
+	defer profiles.RUnlock()
+	profile, ok := profiles.byName[name]
+	return profile, ok
+}
+
+// GCPProfile creates the Google Cloud Logging profile of the project.
+// Cloud Logging correlates the trace only if it has the
+// "projects/<PROJECT_ID>/traces/<TRACE_ID>" form, so the project ID is required.
+// It can be registered by name, e.g.:
+//
+//	otelzap.RegisterProfile("gcp", otelzap.GCPProfile("my-project"))
+func GCPProfile(projectID string) Profile {
+	return Profile{
+		TraceIDKey: "logging.googleapis.com/trace",
+		SpanIDKey:  "logging.googleapis.com/spanId",
+		SampledKey: "logging.googleapis.com/trace_sampled",
+		FormatTraceID: func(id trace.TraceID) string {
+			return "projects/" + projectID + "/traces/" + id.String()
+		},
+	}
+}
+
+// gcpProjectEnv is the environment variable of the Google Cloud project ID.
+const gcpProjectEnv = "GOOGLE_CLOUD_PROJECT"
+
+// gcpEnvProfile creates the Google Cloud Logging profile of the project
+// from the environment, the bare trace ID is used if there is no project.
+func gcpEnvProfile() Profile {
+	profile := GCPProfile("")
+	profile.FormatTraceID = func(id trace.TraceID) string {
+		if projectID := os.Getenv(gcpProjectEnv); projectID != "" {
+			return "projects/" + projectID + "/traces/" + id.String()
+		}
+		return id.String()
+	}
+	return profile
+}
+
+// WithGCPProfile selects the Google Cloud Logging profile
+// of the project, see GCPProfile.
+func WithGCPProfile(projectID string) Option {
+	profile := GCPProfile(projectID)
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// WithProfile selects the names and formats of injected log fields
+// by the built-in (see LookupProfile) or registered profile name
+// (see RegisterProfile).
+func WithProfile(name string) Option {
+	profile, ok := LookupProfile(name)
+	if !ok {
+		handleError(fmt.Errorf("otelzap: unknown profile %q", name))
+	}
+
+	return func(o *options) {
+		if ok {
+			o.profile = profile
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestProfiles unit tests for built-in and custom profiles.
+func TestProfiles(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 0, 0, 0, 0, 0, 0, 1, 0},
+		SpanID:     trace.SpanID{0, 0, 0, 0, 0, 0, 0, 42},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	fields := func(name string) []zapcore.Field {
+		p, ok := LookupProfile(name)
+		assert.True(t, ok, name)
+		return p.Fields(sc)
+	}
+
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("trace_id", "01020304050607080000000000000100"),
+			zap.String("span_id", "000000000000002a"),
+		},
+		fields("otel"))
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("trace.id", "01020304050607080000000000000100"),
+			zap.String("span.id", "000000000000002a"),
+		},
+		fields("ecs"))
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("dd.trace_id", "256"),
+			zap.String("dd.span_id", "42"),
+		},
+		fields("datadog"))
+	gcp := []zapcore.Field{
+		zap.String("logging.googleapis.com/trace", "projects/my-project/traces/01020304050607080000000000000100"),
+		zap.String("logging.googleapis.com/spanId", "000000000000002a"),
+		zap.Bool("logging.googleapis.com/trace_sampled", true),
+	}
+	assert.Equal(t, gcp, GCPProfile("my-project").Fields(sc))
+	assert.Equal(t, gcp, newOptions(WithGCPProfile("my-project")).profile.Fields(sc))
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	assert.Equal(t, gcp, fields("gcp"))
+	assert.Equal(t, gcp, newOptions(WithProfile("gcp")).profile.Fields(sc))
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("logging.googleapis.com/trace", "01020304050607080000000000000100"),
+			zap.String("logging.googleapis.com/spanId", "000000000000002a"),
+			zap.Bool("logging.googleapis.com/trace_sampled", true),
+		},
+		fields("gcp")) // no project
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("traceID", "01020304050607080000000000000100"),
+			zap.String("spanID", "000000000000002a"),
+		},
+		fields("grafana"))
+
+	RegisterProfile("custom", Profile{TraceIDKey: "tid"})
+	assert.Equal(t,
+		[]zapcore.Field{
+			zap.String("tid", "01020304050607080000000000000100"),
+		},
+		fields("custom"))
+
+	assert.Empty(t, Profile{TraceIDKey: "tid", SampledKey: "s"}.Fields(trace.SpanContext{}))
+
+	var errs []error
+	defer func(h func(error)) { handleError = h }(handleError)
+	handleError = func(err error) { errs = append(errs, err) }
+	o := newOptions(WithProfile("ecs"), WithProfile("unknown"))
+	assert.Equal(t, "trace.id", o.profile.TraceIDKey)
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], `otelzap: unknown profile "unknown"`)
+	}
+}
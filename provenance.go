@@ -0,0 +1,65 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field provenance values, see WithFieldProvenance.
+const (
+	ProvenanceWith     = "with"
+	ProvenanceCallSite = "call"
+	ProvenanceExtra    = "extra"
+)
+
+// provenanceSuffix is the suffix of companion provenance attributes.
+const provenanceSuffix = ".src"
+
+// WithFieldProvenance adds a companion "<key>.src" attribute to each
+// event attribute telling where it came from: "with" for logger.With()
+// fields, "call" for call-site fields and "extra" for attributes added
+// by the span logger itself (e.g. "zap.level").
+//
+// This is a debug option to find the origin of unexpected attributes,
+// it doubles the number of attributes.
+func WithFieldProvenance() Option {
+	return func(o *options) {
+		o.provenance = true
+	}
+}
+
+// attributesWithProvenance converts ZAP fields into attributes
+// with companion provenance attributes.
+func (o *options) attributesWithProvenance(with, fields []zapcore.Field, callSiteFirst bool) []attribute.KeyValue {
+	if len(with)+len(fields) == 0 {
+		return nil // no fields
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 2*EstimateAttrs(with, fields))
+	if callSiteFirst {
+		attrs = o.appendWithProvenance(attrs, fields, ProvenanceCallSite)
+		attrs = o.appendWithProvenance(attrs, with, ProvenanceWith)
+	} else {
+		attrs = o.appendWithProvenance(attrs, with, ProvenanceWith)
+		attrs = o.appendWithProvenance(attrs, fields, ProvenanceCallSite)
+	}
+	return attrs
+}
+
+// appendWithProvenance converts and appends ZAP fields with provenance.
+func (o *options) appendWithProvenance(attrs []attribute.KeyValue, fields []zapcore.Field, src string) []attribute.KeyValue {
+	for _, field := range fields {
+		n := len(attrs)
+		attrs = o.appendZapFields(attrs, field)
+		attrs = appendProvenance(attrs, attrs[n:], src)
+	}
+	return attrs
+}
+
+// appendProvenance appends a companion provenance attribute of each attribute.
+func appendProvenance(attrs []attribute.KeyValue, from []attribute.KeyValue, src string) []attribute.KeyValue {
+	for i, n := 0, len(from); i < n; i++ {
+		attrs = append(attrs, attribute.String(string(from[i].Key)+provenanceSuffix, src))
+	}
+	return attrs
+}
@@ -0,0 +1,44 @@
+package otelzap_test
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestSpanLoggerFieldProvenance(t *testing.T) {
+	span := newRecordingSpan(t)
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithFieldProvenance()).
+		With(zap.String("foo", "bar"))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("zap.level.src", "extra"),
+				attribute.String("foo", "bar"),
+				attribute.String("foo.src", "with"),
+				attribute.Int("baz", 123),
+				attribute.String("baz.src", "call"),
+			))
+	SL.Info("my message", zap.Int("baz", 123))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.Int("baz", 123),
+				attribute.String("baz.src", "call"),
+				attribute.String("foo", "bar"),
+				attribute.String("foo.src", "with"),
+				attribute.String("zap.level", "info"),
+				attribute.String("zap.level.src", "extra"),
+			))
+	SpanLogger(span, L, WithFieldProvenance(), WithFieldOrder(FieldOrderCallSiteFirst)).
+		With(zap.String("foo", "bar")).
+		Info("my message", zap.Int("baz", 123))
+}
@@ -0,0 +1,44 @@
+package otelzap
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// WithRecordError calls span.RecordError for each zap.Error field
+// of Error (and above) entries, so backends that special-case
+// exception events pick the errors up.
+// If replace is true the error fields are omitted from the log event.
+// Only the call-site fields are recorded, the errors bound with
+// logger.With() are kept as attributes and not recorded on every entry.
+func WithRecordError(replace bool) Option {
+	return func(o *options) {
+		o.recordError = true
+		o.recordErrorReplace = replace
+	}
+}
+
+// recordErrors records the errors of the fields on the span
+// and returns the fields without errors if they are replaced.
+func (zs zapSpanCore) recordErrors(fields []zapcore.Field) []zapcore.Field {
+	n := 0
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok && err != nil {
+				zs.span.RecordError(err)
+				n++
+			}
+		}
+	}
+	if n == 0 || !zs.opts.recordErrorReplace {
+		return fields
+	}
+
+	out := make([]zapcore.Field, 0, len(fields)-n)
+	for _, f := range fields {
+		if err, ok := f.Interface.(error); f.Type == zapcore.ErrorType && ok && err != nil {
+			continue // recorded
+		}
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package otelzap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// WithRepanic makes Recover and RecoverMiddleware re-panic
+// after the panic is logged and recorded on the span.
+func WithRepanic() Option {
+	return func(o *options) {
+		o.repanic = true
+	}
+}
+
+// Recover recovers from panic, logs the panic value and stack via the span
+// logger of the context, records the exception on the span and sets
+// the error status. Should be deferred directly:
+//
+//	defer otelzap.Recover(ctx, logger)
+//
+// The panic is swallowed unless WithRepanic is provided.
+func Recover(ctx context.Context, logger *zap.Logger, opts ...Option) {
+	if r := recover(); r != nil {
+		handlePanic(ctx, logger, opts, r)
+	}
+}
+
+// RecoverMiddleware creates HTTP middleware which recovers from
+// the handler panics as Recover does and responds with
+// 500 Internal Server Error, unless WithRepanic is provided.
+func RecoverMiddleware(logger *zap.Logger, opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if p := recover(); p != nil {
+					if p == http.ErrAbortHandler {
+						panic(p) // see http.ErrAbortHandler
+					}
+					handlePanic(r.Context(), logger, opts, p)
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handlePanic logs and records the panic, re-panics if needed.
+func handlePanic(ctx context.Context, logger *zap.Logger, opts []Option, p interface{}) {
+	err, ok := p.(error)
+	if !ok {
+		err = fmt.Errorf("panic: %v", p)
+	}
+
+	SpanLoggerFromContext(ctx, logger, opts...).
+		Error("panic recovered",
+			zap.Any("panic", p),
+			zap.StackSkip("stacktrace", 2))
+
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		span.RecordError(err,
+			trace.WithStackTrace(true),
+			trace.WithAttributes(semconv.ExceptionEscapedKey.Bool(true)))
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	if newOptions(opts...).repanic {
+		panic(p)
+	}
+}
@@ -0,0 +1,74 @@
+package otelzap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestRecover(t *testing.T) {
+	span := newRecordingSpan(t)
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	L, buf := newJSONLogger()
+
+	gomock.InOrder(
+		span.EXPECT().AddEvent("panic recovered", gomock.Any()),
+		span.EXPECT().RecordError(assert.AnError, gomock.Any()),
+		span.EXPECT().SetStatus(codes.Error, assert.AnError.Error()),
+	)
+	func() {
+		defer Recover(ctx, L)
+		panic(assert.AnError)
+	}()
+	assert.Contains(t, buf.String(), `"level":"error","msg":"panic recovered","panic":"assert.AnError general error for testing","stacktrace":"`)
+
+	// re-panic
+	span.EXPECT().AddEvent("panic recovered", gomock.Any())
+	span.EXPECT().RecordError(gomock.Any(), gomock.Any())
+	span.EXPECT().SetStatus(codes.Error, "panic: oops")
+	assert.PanicsWithValue(t, "oops", func() {
+		defer Recover(ctx, L, WithRepanic())
+		panic("oops")
+	})
+
+	// no panic
+	func() {
+		defer Recover(ctx, L)
+	}()
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	span := newRecordingSpan(t)
+	L, _ := newJSONLogger()
+
+	handler := RecoverMiddleware(L)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		}))
+
+	span.EXPECT().AddEvent("panic recovered", gomock.Any())
+	span.EXPECT().RecordError(gomock.Any(), gomock.Any())
+	span.EXPECT().SetStatus(codes.Error, "panic: oops")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r.WithContext(trace.ContextWithSpan(r.Context(), span)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// abort is not recovered
+	abort := RecoverMiddleware(L)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,151 @@
+package otelzap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReadExportedEvents reads span events written by WithEventExport.
+// Empty lines are ignored.
+func ReadExportedEvents(r io.Reader) ([]ExportedEvent, error) {
+	var events []ExportedEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024) // events may be large
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue // empty line
+		}
+
+		var ev ExportedEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, fmt.Errorf("otelzap: line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Time gets the event timestamp.
+func (ev ExportedEvent) Time() time.Time {
+	ns, _ := strconv.ParseInt(ev.TimeUnixNano, 10, 64)
+	return time.Unix(0, ns)
+}
+
+// KeyValues converts the event attributes back to OpenTelemetry attributes.
+func (ev ExportedEvent) KeyValues() []attribute.KeyValue {
+	if len(ev.Attributes) == 0 {
+		return nil
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(ev.Attributes))
+	for _, a := range ev.Attributes {
+		attrs = append(attrs, attribute.KeyValue{
+			Key:   attribute.Key(a.Key),
+			Value: a.Value.value(),
+		})
+	}
+	return attrs
+}
+
+// value converts OTLP-like value back to attribute value.
+// Nested arrays are not supported by OpenTelemetry attributes,
+// the array type is defined by its first item.
+func (v ExportedValue) value() attribute.Value {
+	switch {
+	case v.StringValue != nil:
+		return attribute.StringValue(*v.StringValue)
+	case v.BoolValue != nil:
+		return attribute.BoolValue(*v.BoolValue)
+	case v.IntValue != nil:
+		i, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+		return attribute.Int64Value(i)
+	case v.DoubleValue != nil:
+		return attribute.Float64Value(*v.DoubleValue)
+	case v.ArrayValue != nil:
+		return v.ArrayValue.value()
+	}
+	return attribute.StringValue("") // empty value
+}
+
+// value converts OTLP-like array back to attribute slice value.
+func (a ExportedArrayValue) value() attribute.Value {
+	if len(a.Values) == 0 {
+		return attribute.StringSliceValue(nil)
+	}
+
+	switch first := a.Values[0]; {
+	case first.BoolValue != nil:
+		return attribute.BoolSliceValue(importArray(a.Values, attribute.Value.AsBool))
+	case first.IntValue != nil:
+		return attribute.Int64SliceValue(importArray(a.Values, attribute.Value.AsInt64))
+	case first.DoubleValue != nil:
+		return attribute.Float64SliceValue(importArray(a.Values, attribute.Value.AsFloat64))
+	}
+	return attribute.StringSliceValue(importArray(a.Values, attribute.Value.AsString))
+}
+
+// importArray converts OTLP-like array items back to slice.
+func importArray[T any](values []ExportedValue, as func(attribute.Value) T) []T {
+	out := make([]T, 0, len(values))
+	for _, v := range values {
+		out = append(out, as(v.value()))
+	}
+	return out
+}
+
+// Replay adds the recorded events to the span,
+// keeping their names, timestamps and attributes.
+func Replay(span trace.Span, events []ExportedEvent) {
+	for _, ev := range events {
+		span.AddEvent(ev.Name,
+			trace.WithTimestamp(ev.Time()),
+			trace.WithAttributes(ev.KeyValues()...))
+	}
+}
+
+// DiffEvents compares the recorded events with the actual ones
+// by names and attributes, ignoring timestamps and trace/span IDs
+// which differ between runs. Returns human-readable description
+// of the first difference or an empty string if events are equal:
+//
+//	golden, _ := otelzap.ReadExportedEvents(file)
+//	if diff := otelzap.DiffEvents(golden, actual); diff != "" {
+//		t.Error(diff)
+//	}
+func DiffEvents(expected, actual []ExportedEvent) string {
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		exp, act := expected[i], actual[i]
+		if exp.Name != act.Name {
+			return fmt.Sprintf("event #%d: expected name %q, actual %q", i, exp.Name, act.Name)
+		}
+
+		expAttrs, actAttrs := exp.KeyValues(), act.KeyValues()
+		if !equalAttributes(expAttrs, actAttrs) {
+			return fmt.Sprintf("event #%d %q: expected attributes:\n%s\nactual attributes:\n%s",
+				i, exp.Name, Sprint(expAttrs), Sprint(actAttrs))
+		}
+	}
+
+	switch {
+	case len(expected) > len(actual):
+		return fmt.Sprintf("expected %d events, actual %d: missing %q",
+			len(expected), len(actual), expected[len(actual)].Name)
+	case len(expected) < len(actual):
+		return fmt.Sprintf("expected %d events, actual %d: unexpected %q",
+			len(expected), len(actual), actual[len(expected)].Name)
+	}
+
+	return "" // equal
+}
@@ -0,0 +1,78 @@
+package otelzap_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestReplay(t *testing.T) {
+	span := newRecordingSpan(t)
+	span.EXPECT().SpanContext().Return(trace.SpanContext{}).AnyTimes()
+	span.EXPECT().AddEvent(gomock.Any(), gomock.Any()).AnyTimes()
+
+	var buf bytes.Buffer
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithEventExport(&buf))
+	SL.Info("first", zap.Int("foo", 123), zap.Bools("ok", []bool{true}))
+	SL.Warn("second", zap.Strings("bar", []string{"a", "b"}), zap.Float64s("pi", []float64{3.5}))
+
+	golden, err := ReadExportedEvents(strings.NewReader(buf.String() + "\n"))
+	require.NoError(t, err)
+	require.Len(t, golden, 2)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("zap.level", "info"),
+		attribute.Int("foo", 123),
+		attribute.BoolSlice("ok", []bool{true}),
+	}, golden[0].KeyValues())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("zap.level", "warn"),
+		attribute.StringSlice("bar", []string{"a", "b"}),
+		attribute.Float64Slice("pi", []float64{3.5}),
+	}, golden[1].KeyValues())
+
+	// the same output on the next run
+	buf.Reset()
+	SL.Info("first", zap.Int("foo", 123), zap.Bools("ok", []bool{true}))
+	SL.Warn("second", zap.Strings("bar", []string{"a", "b"}), zap.Float64s("pi", []float64{3.5}))
+	actual, err := ReadExportedEvents(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, DiffEvents(golden, actual))
+
+	assert.Equal(t, `event #1 "second": expected attributes:`+"\n"+
+		`zap.level = "warn"`+"\n"+
+		`bar       = [a b]`+"\n"+
+		`pi        = [3.5]`+"\n"+
+		`actual attributes:`+"\n"+
+		`zap.level = "warn"`, DiffEvents(golden, []ExportedEvent{
+		actual[0], {Name: "second", Attributes: actual[1].Attributes[:1]},
+	}))
+	assert.Equal(t, `event #0: expected name "first", actual "third"`,
+		DiffEvents(golden, []ExportedEvent{{Name: "third"}}))
+	assert.Equal(t, `expected 2 events, actual 1: missing "second"`,
+		DiffEvents(golden, actual[:1]))
+	assert.Equal(t, `expected 1 events, actual 2: unexpected "second"`,
+		DiffEvents(golden[:1], actual))
+
+	_, err = ReadExportedEvents(strings.NewReader("{}\nbad"))
+	assert.ErrorContains(t, err, "line 2")
+
+	// replay
+	target := newRecordingSpan(t)
+	target.EXPECT().AddEvent("first", trace.WithTimestamp(golden[0].Time()),
+		trace.WithAttributes(golden[0].KeyValues()...))
+	target.EXPECT().AddEvent("second", trace.WithTimestamp(golden[1].Time()),
+		trace.WithAttributes(golden[1].KeyValues()...))
+	Replay(target, golden)
+	assert.WithinDuration(t, time.Now(), golden[0].Time(), time.Minute)
+}
@@ -0,0 +1,70 @@
+package otelzap
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// RetryPolicy defines how many times and how often to retry.
+type RetryPolicy struct {
+	MaxAttempts int           // the number of attempts, at least one
+	Delay       time.Duration // delay before the second attempt
+	MaxDelay    time.Duration // max delay, 0 means unlimited
+	Multiplier  float64       // delay multiplier, 1 if not positive
+}
+
+// next gets the delay after the current one.
+func (p RetryPolicy) next(delay time.Duration) time.Duration {
+	if p.Multiplier > 0 {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// LogRetries calls the operation until it succeeds or the attempts
+// are exhausted (or the context is done), waiting between the attempts
+// according to the policy. Each failed attempt is logged as a warning
+// with "retry.attempt", "retry.delay" and the error, and the final
+// outcome is logged too, via the span logger of the context.
+// If all the attempts fail, the error is also recorded on the span.
+// Returns the last error.
+func LogRetries(ctx context.Context, logger *zap.Logger, op func() error, policy RetryPolicy) error {
+	log := SpanLoggerFromContext(ctx, logger)
+
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			log.Info("operation succeeded", zap.Int("retry.attempts", attempt))
+			return nil
+		}
+
+		if attempt >= policy.MaxAttempts {
+			log.Error("operation failed", zap.Int("retry.attempts", attempt), zap.Error(err))
+			RecordError(trace.SpanFromContext(ctx), err, zap.Int("retry.attempts", attempt))
+			return err
+		}
+
+		log.Warn("operation attempt failed",
+			zap.Int("retry.attempt", attempt),
+			zap.Duration("retry.delay", delay),
+			zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Error("operation cancelled", zap.Int("retry.attempts", attempt), zap.Error(err))
+			RecordError(trace.SpanFromContext(ctx), err, zap.Int("retry.attempts", attempt))
+			return err
+		case <-timer.C:
+		}
+		delay = policy.next(delay)
+	}
+}
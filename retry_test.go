@@ -0,0 +1,82 @@
+package otelzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestLogRetries(t *testing.T) {
+	span := newRecordingSpan(t)
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	L, _ := newJSONLogger()
+
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		Delay:       time.Millisecond,
+		Multiplier:  2,
+	}
+
+	// succeeds on the second attempt
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("operation attempt failed",
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Int("retry.attempt", 1),
+					attribute.String("retry.delay", "1ms"),
+					attribute.String("error", "assert.AnError general error for testing"),
+				)),
+		span.EXPECT().
+			AddEvent("operation succeeded",
+				trace.WithAttributes(
+					attribute.String("zap.level", "info"),
+					attribute.Int("retry.attempts", 2),
+				)),
+	)
+	calls := 0
+	assert.NoError(t, LogRetries(ctx, L, func() error {
+		calls++
+		if calls < 2 {
+			return assert.AnError
+		}
+		return nil
+	}, policy))
+	assert.Equal(t, 2, calls)
+
+	// all attempts fail
+	gomock.InOrder(
+		span.EXPECT().AddEvent("operation attempt failed", gomock.Any()).Times(2),
+		span.EXPECT().AddEvent("operation failed", gomock.Any()),
+		span.EXPECT().RecordError(assert.AnError, gomock.Any()),
+		span.EXPECT().SetStatus(codes.Error, assert.AnError.Error()),
+	)
+	calls = 0
+	err := LogRetries(ctx, L, func() error {
+		calls++
+		return assert.AnError
+	}, policy)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 3, calls)
+}
+
+func TestLogRetriesCancelled(t *testing.T) {
+	L, buf := newJSONLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := errors.New("failed")
+	assert.Same(t, err, LogRetries(ctx, L, func() error { return err },
+		RetryPolicy{MaxAttempts: 10, Delay: time.Hour}))
+	assert.Equal(t, `{"level":"warn","msg":"operation attempt failed","retry.attempt":1,"retry.delay":"1h0m0s","error":"failed"}`+"\n"+
+		`{"level":"error","msg":"operation cancelled","retry.attempts":1,"error":"failed"}`, buf.Stripped())
+}
@@ -0,0 +1,53 @@
+package otelzap
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithAddEventTimeout limits the time a span may spend in AddEvent,
+// so a misbehaving span implementation which blocks does not block
+// the logging. The timeouts are reported to the OpenTelemetry error handler.
+// Note the blocked AddEvent call is not cancelled, it is just abandoned.
+func WithAddEventTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.addEventTimeout = timeout
+	}
+}
+
+// addEvent adds the event to the span, isolating the logging
+// from the span implementation panics and, optionally, timeouts.
+func (o *options) addEvent(span trace.Span, name string, opts ...trace.EventOption) {
+	if o.addEventTimeout <= 0 {
+		safeAddEvent(span, name, opts...)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		safeAddEvent(span, name, opts...)
+	}()
+
+	timer := time.NewTimer(o.addEventTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		handleError(fmt.Errorf("otelzap: AddEvent(%q) timed out after %s", name, o.addEventTimeout))
+	}
+}
+
+// safeAddEvent adds the event to the span recovering from panics.
+// The panics are reported to the OpenTelemetry error handler.
+func safeAddEvent(span trace.Span, name string, opts ...trace.EventOption) {
+	defer func() {
+		if r := recover(); r != nil {
+			handleError(fmt.Errorf("otelzap: AddEvent(%q) panicked: %v", name, r))
+		}
+	}()
+
+	span.AddEvent(name, opts...)
+}
@@ -0,0 +1,43 @@
+package otelzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/Pilatuz/otelzap/internal/mocked"
+)
+
+// TestSafeAddEvent unit tests for AddEvent isolation.
+func TestSafeAddEvent(t *testing.T) {
+	var errs []error
+	defer func(h func(error)) { handleError = h }(handleError)
+	handleError = func(err error) { errs = append(errs, err) }
+
+	ctrl := gomock.NewController(t)
+	span := NewMockedSpan(ctrl)
+
+	span.EXPECT().AddEvent("ok")
+	newOptions().addEvent(span, "ok")
+	assert.Empty(t, errs)
+
+	span.EXPECT().AddEvent("panic").Do(func(string, ...interface{}) { panic("oops") })
+	newOptions().addEvent(span, "panic")
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], `otelzap: AddEvent("panic") panicked: oops`)
+	}
+
+	release := make(chan struct{})
+	span.EXPECT().AddEvent("block").Do(func(string, ...interface{}) { <-release })
+	newOptions(WithAddEventTimeout(time.Millisecond)).addEvent(span, "block")
+	close(release)
+	if assert.Len(t, errs, 2) {
+		assert.EqualError(t, errs[1], `otelzap: AddEvent("block") timed out after 1ms`)
+	}
+
+	span.EXPECT().AddEvent("fast")
+	newOptions(WithAddEventTimeout(time.Minute)).addEvent(span, "fast")
+	assert.Len(t, errs, 2)
+}
@@ -0,0 +1,80 @@
+package otelzap
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// sampledOutCountKey is the span attribute key of sampled out entries count.
+const sampledOutCountKey = attribute.Key("log.sampled_out_count")
+
+// SamplingHook creates a sampler hook (see zapcore.SamplerHook)
+// which counts the entries dropped by sampler and sets
+// the "log.sampled_out_count" span attribute.
+//
+// Note, the sampling configured on the logger passed to SpanLogger
+// does not affect span events, since span core is teed outside of the sampler.
+// But if sampler wraps the span logger, the dropped entries never reach the span,
+// this hook can be used to see the number of such entries:
+//
+//	logger = otelzap.SpanLogger(span, logger).
+//		WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+//			return zapcore.NewSamplerWithOptions(core, time.Second, 10, 100,
+//				zapcore.SamplerHook(otelzap.SamplingHook(span)))
+//		}))
+func SamplingHook(span trace.Span) func(zapcore.Entry, zapcore.SamplingDecision) {
+	var dropped int64
+	return func(_ zapcore.Entry, decision zapcore.SamplingDecision) {
+		if decision&zapcore.LogDropped == 0 {
+			return // not dropped
+		}
+
+		n := atomic.AddInt64(&dropped, 1)
+		if span != nil && span.IsRecording() {
+			span.SetAttributes(sampledOutCountKey.Int64(n))
+		}
+	}
+}
+
+// Event sampling summary event name and attributes.
+const (
+	samplingEventName  = "log.sampling"
+	suppressedCountKey = attribute.Key("log.suppressed_count")
+)
+
+// sample checks if event with the message should be written.
+// The first events per message are always written, then every Mth.
+func (st *spanState) sample(message string, first, thereafter int) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.sampled == nil {
+		st.sampled = make(map[string]int)
+	}
+	n := st.sampled[message] + 1
+	st.sampled[message] = n
+
+	if n <= first || (thereafter > 0 && (n-first)%thereafter == 0) {
+		return true
+	}
+
+	st.suppressed++
+	return false
+}
+
+// flushSampling writes the number of suppressed events as a summary event.
+func (zs zapSpanCore) flushSampling() {
+	st := zs.state
+	st.mu.Lock()
+	n := st.suppressed
+	st.suppressed = 0
+	st.mu.Unlock()
+
+	if n > 0 {
+		zs.addEventAs(samplingEventName,
+			[]attribute.KeyValue{suppressedCountKey.Int(n)}, nil, nil)
+	}
+}
@@ -0,0 +1,94 @@
+package otelzap
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// extrasKey identifies the events having extra attributes only.
+type extrasKey struct {
+	level  zapcore.Level
+	logger string
+}
+
+// extrasEvent is a cached event having extra attributes only.
+type extrasEvent struct {
+	attrs []attribute.KeyValue // capacity is limited, so append copies
+	opts  []trace.EventOption  // trace.WithAttributes(attrs...)
+}
+
+// staticExtras checks if the events without fields depend
+// on the level and the logger name only, so they can be cached.
+func (o *options) staticExtras() bool {
+	return !o.deterministic && !o.provenance &&
+		o.tenantKey == "" && len(o.securityMessages) == 0 &&
+		o.normalizeName == nil && !o.messageTemplates && !o.callerAttrs
+}
+
+// extrasOnly gets the cached event having extra attributes only,
+// so the frequent calls without fields do not allocate.
+func (st *spanState) extrasOnly(zs zapSpanCore, entry zapcore.Entry) extrasEvent {
+	key := extrasKey{level: entry.Level, logger: entry.LoggerName}
+
+	st.mu.Lock()
+	ev, ok := st.extras[key]
+	st.mu.Unlock()
+	if ok {
+		return ev
+	}
+
+	attrs := zs.attributes(entry, nil, nil)
+	attrs = attrs[:len(attrs):len(attrs)]
+	ev = extrasEvent{
+		attrs: attrs,
+		opts:  []trace.EventOption{trace.WithAttributes(attrs...)},
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.extras == nil {
+		st.extras = make(map[extrasKey]extrasEvent)
+	}
+	st.extras[key] = ev
+	return ev
+}
+
+// attrsScratch is a reusable attribute buffer, see scratchPool.
+type attrsScratch struct {
+	attrs []attribute.KeyValue
+	opts  []trace.EventOption
+}
+
+// scratchPool is a pool of attribute buffers reused by the events
+// having a few fields, if the attributes are not kept after AddEvent
+// (see reuseScratch). Span implementations copy the attributes.
+var scratchPool = sync.Pool{
+	New: func() interface{} {
+		return &attrsScratch{
+			attrs: make([]attribute.KeyValue, 0, 16),
+			opts:  make([]trace.EventOption, 0, 2),
+		}
+	},
+}
+
+// reuseScratch checks if the event attributes can be reused after AddEvent.
+func (o *options) reuseScratch() bool {
+	return o.dedupWindow <= 0 && // keeps the last attributes
+		o.addEventTimeout <= 0 // AddEvent might be still in progress
+}
+
+// put returns the scratch buffers back to the pool.
+func (s *attrsScratch) put() {
+	all := s.attrs[:cap(s.attrs)]
+	for i := range all {
+		all[i] = attribute.KeyValue{} // do not hold references
+	}
+	for i := range s.opts {
+		s.opts[i] = nil
+	}
+	s.opts = s.opts[:0]
+	scratchPool.Put(s)
+}
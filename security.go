@@ -0,0 +1,89 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// securitySeverityKey is the attribute key of security-relevant events.
+const securitySeverityKey = attribute.Key("security.severity")
+
+// SecuritySeverity is the severity of security-relevant events.
+type SecuritySeverity int
+
+// Known security severities.
+const (
+	SecurityLow SecuritySeverity = iota + 1
+	SecurityMedium
+	SecurityHigh
+	SecurityCritical
+)
+
+// String gets the severity name.
+func (s SecuritySeverity) String() string {
+	switch s {
+	case SecurityLow:
+		return "low"
+	case SecurityMedium:
+		return "medium"
+	case SecurityHigh:
+		return "high"
+	case SecurityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// WithSecurityKeys registers field keys as security-relevant.
+// The entries with any of the fields get "security.severity" attribute
+// and are never sampled, deduplicated or truncated (see WithMaxEvents).
+// If many registrations match, the highest severity is used.
+func WithSecurityKeys(severity SecuritySeverity, keys ...string) Option {
+	return func(o *options) {
+		if o.securityKeys == nil {
+			o.securityKeys = make(map[string]SecuritySeverity, len(keys))
+		}
+		addSeverity(o.securityKeys, severity, keys)
+	}
+}
+
+// WithSecurityMessages registers messages as security-relevant,
+// the same way as WithSecurityKeys does for field keys.
+func WithSecurityMessages(severity SecuritySeverity, messages ...string) Option {
+	return func(o *options) {
+		if o.securityMessages == nil {
+			o.securityMessages = make(map[string]SecuritySeverity, len(messages))
+		}
+		addSeverity(o.securityMessages, severity, messages)
+	}
+}
+
+// addSeverity registers names keeping the highest severity.
+func addSeverity(m map[string]SecuritySeverity, severity SecuritySeverity, names []string) {
+	for _, name := range names {
+		if severity > m[name] {
+			m[name] = severity
+		}
+	}
+}
+
+// securitySeverity gets the highest severity of the entry,
+// zero if the entry is not security-relevant.
+func (o *options) securitySeverity(entry zapcore.Entry, with, fields []zapcore.Field) SecuritySeverity {
+	if len(o.securityKeys)+len(o.securityMessages) == 0 {
+		return 0 // disabled
+	}
+
+	severity := o.securityMessages[entry.Message]
+	if len(o.securityKeys) != 0 {
+		for _, ff := range [2][]zapcore.Field{with, fields} {
+			for _, field := range ff {
+				if s := o.securityKeys[field.Key]; s > severity {
+					severity = s
+				}
+			}
+		}
+	}
+
+	return severity
+}
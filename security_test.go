@@ -0,0 +1,61 @@
+package otelzap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestSecuritySeverity(t *testing.T) {
+	assert.Equal(t, "low", SecurityLow.String())
+	assert.Equal(t, "critical", SecurityCritical.String())
+	assert.Equal(t, "unknown", SecuritySeverity(0).String())
+}
+
+func TestSpanLoggerSecurity(t *testing.T) {
+	span := newRecordingSpan(t)
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L,
+		WithEventSampling(1, 0),
+		WithMaxEvents(1),
+		WithSecurityMessages(SecurityMedium, "login failed"),
+		WithSecurityKeys(SecurityLow, "user"),
+		WithSecurityKeys(SecurityHigh, "token"))
+
+	span.EXPECT().
+		AddEvent("login failed",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("user", "bob"),
+				attribute.String("security.severity", "medium"),
+				)).
+		Times(3) // never sampled or truncated
+	for i := 0; i < 3; i++ {
+		SL.Info("login failed", zap.String("user", "bob"))
+	}
+
+	span.EXPECT().
+		AddEvent("token rejected",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("user", "bob"),
+				attribute.String("token", "***"),
+				attribute.String("security.severity", "high"),
+			))
+	SL.With(zap.String("user", "bob")).
+		Info("token rejected", zap.String("token", "***"))
+
+	span.EXPECT().
+		AddEvent("other",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+			))
+	SL.Info("other")
+	SL.Info("other") // sampled
+	SL.Info("more")  // truncated
+}
@@ -0,0 +1,181 @@
+package otelzap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// SinkScheme is the scheme of the span sink, see RegisterSink.
+//
+// Adding "otelspan://" to zap.Config.OutputPaths writes each JSON-encoded
+// log line as an event of the active span identified by the line's
+// span ID field. The spans are tracked by SinkSpanProcessor which should
+// be registered with the tracer provider:
+//
+//	sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(otelzap.SinkSpanProcessor()))
+//
+// The field names can be changed by URL query, defaults are:
+//
+//	otelspan://?span_id=span_id&trace_id=trace_id&message=msg&level=level
+//
+// Unlike SpanLogger, the log lines need to be JSON-encoded
+// and contain the span ID (see WithProfile).
+const SinkScheme = "otelspan"
+
+// RegisterSink registers the "otelspan" sink with zap (see SinkScheme),
+// so it can be used in zap.Config.OutputPaths. The options
+// (e.g. WithAddEventTimeout) apply to the events written by the sink.
+// The sink can be registered once per process.
+func RegisterSink(opts ...Option) error {
+	o := newOptions(opts...)
+	return zap.RegisterSink(SinkScheme, func(u *url.URL) (zap.Sink, error) {
+		return newSpanSink(u, o)
+	})
+}
+
+// sinkSpans are the active spans tracked by SinkSpanProcessor.
+var sinkSpans = struct {
+	sync.RWMutex
+	byID map[trace.SpanID]trace.Span
+}{
+	byID: make(map[trace.SpanID]trace.Span),
+}
+
+// sinkProcessor tracks the active spans.
+type sinkProcessor struct{}
+
+// SinkSpanProcessor creates span processor tracking the active spans
+// for "otelspan://" sink, see SinkScheme.
+func SinkSpanProcessor() sdktrace.SpanProcessor {
+	return sinkProcessor{}
+}
+
+// OnStart registers the started span.
+func (sinkProcessor) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	if !span.IsRecording() {
+		return // nothing to write to
+	}
+
+	sinkSpans.Lock()
+	sinkSpans.byID[span.SpanContext().SpanID()] = span
+	sinkSpans.Unlock()
+}
+
+// OnEnd unregisters the ended span.
+func (sinkProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	sinkSpans.Lock()
+	delete(sinkSpans.byID, span.SpanContext().SpanID())
+	sinkSpans.Unlock()
+}
+
+// Shutdown does nothing.
+func (sinkProcessor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush does nothing.
+func (sinkProcessor) ForceFlush(context.Context) error { return nil }
+
+// spanSink writes JSON log lines to the active spans.
+type spanSink struct {
+	opts       *options
+	spanIDKey  string
+	traceIDKey string
+	messageKey string
+	levelKey   string
+}
+
+// newSpanSink creates a new span sink from URL.
+func newSpanSink(u *url.URL, o *options) (zap.Sink, error) {
+	sink := &spanSink{
+		opts:       o,
+		spanIDKey:  "span_id",
+		traceIDKey: "trace_id",
+		messageKey: "msg",
+		levelKey:   "level",
+	}
+
+	query := u.Query()
+	for name, key := range map[string]*string{
+		"span_id":  &sink.spanIDKey,
+		"trace_id": &sink.traceIDKey,
+		"message":  &sink.messageKey,
+		"level":    &sink.levelKey,
+	} {
+		if values, ok := query[name]; ok {
+			*key = values[len(values)-1]
+			query.Del(name)
+		}
+	}
+	for name := range query {
+		return nil, fmt.Errorf("otelzap: unknown %s sink parameter %q", SinkScheme, name)
+	}
+
+	return sink, nil
+}
+
+// Write writes each log line to its span.
+func (s *spanSink) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) != 0 {
+			s.writeLine(line)
+		}
+	}
+	return len(p), nil
+}
+
+// writeLine writes a single log line as span event.
+func (s *spanSink) writeLine(line []byte) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return // not a JSON object, ignore
+	}
+
+	id, _ := fields[s.spanIDKey].(string)
+	spanID, err := trace.SpanIDFromHex(id)
+	if err != nil {
+		return // no span
+	}
+	sinkSpans.RLock()
+	span, ok := sinkSpans.byID[spanID]
+	sinkSpans.RUnlock()
+	if !ok {
+		return // unknown or ended span
+	}
+
+	name, _ := fields[s.messageKey].(string)
+	delete(fields, s.messageKey)
+	delete(fields, s.spanIDKey)
+	delete(fields, s.traceIDKey)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // stable order
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		if key == s.levelKey {
+			attrs = append(attrs, Any("zap.level", fields[key]))
+			continue
+		}
+		attrs = append(attrs, Any(key, fields[key]))
+	}
+
+	s.opts.addEvent(span, name, trace.WithAttributes(attrs...))
+}
+
+// Sync does nothing, events are written immediately.
+func (s *spanSink) Sync() error { return nil }
+
+// Close does nothing.
+func (s *spanSink) Close() error { return nil }
@@ -0,0 +1,57 @@
+package otelzap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestSpanSink(t *testing.T) {
+	require.NoError(t, RegisterSink())
+	assert.Error(t, RegisterSink()) // already registered
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(SinkSpanProcessor()),
+		sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{SinkScheme + "://"}
+	cfg.EncoderConfig.TimeKey = ""
+	cfg.EncoderConfig.CallerKey = ""
+	L, err := cfg.Build()
+	require.NoError(t, err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "my-span")
+	L.With(zap.String("span_id", span.SpanContext().SpanID().String())).
+		Info("my message", zap.String("foo", "bar"), zap.Bool("ok", true))
+	L.Info("no span")
+	span.End()
+	L.With(zap.String("span_id", span.SpanContext().SpanID().String())).
+		Info("ended span")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "my message", events[0].Name)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("foo", "bar"),
+		attribute.String("zap.level", "info"),
+		attribute.Bool("ok", true),
+	}, events[0].Attributes)
+
+	_, _, err = zap.Open(SinkScheme + "://?message=message&level=lvl")
+	assert.NoError(t, err)
+	_, _, err = zap.Open(SinkScheme + "://?unknown=1")
+	assert.ErrorContains(t, err, `unknown otelspan sink parameter "unknown"`)
+}
@@ -2,80 +2,356 @@ package otelzap
 
 import (
 	"context"
+	"reflect"
+	"strings"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 // SpanLogger creates ZAP logger which also writes to OpenTelemetry span.
 // If span is `nil“ or `no-op` then the same logger returned.
-func SpanLogger(span trace.Span, logger *zap.Logger) *zap.Logger {
+// If the logger already writes to the same span it is returned unchanged,
+// if it writes to another span then that span is replaced.
+func SpanLogger(span trace.Span, logger *zap.Logger, opts ...Option) *zap.Logger {
 	if span == nil || !span.IsRecording() {
 		return logger // no tracing enabled
 	}
+	if tee, ok := logger.Core().(spanTee); ok && sameSpan(tee.span.span, span) {
+		return logger // already wrapped
+	}
+
+	o := newOptions(opts...)
+	if fields := o.traceFields(span); len(fields) != 0 {
+		logger = logger.With(fields...) // log output only
+	}
 
+	extra := o.spanAttributes(span)
+	state := newSpanState()
+	debug := o.debugTriggered(span)
 	wrap := func(core zapcore.Core) zapcore.Core {
-		return zapcore.NewTee(core,
-			zapSpanCore{
-				core: core,
-				span: span,
-			})
+		var with []zapcore.Field
+		if tee, ok := core.(spanTee); ok {
+			core = tee.core // replace the span
+			with = tee.span.with
+		}
+		var level zapcore.LevelEnabler = o.spanLevelOr(core)
+		if debug {
+			level = zapcore.DebugLevel
+		}
+		return spanTee{
+			core: core,
+			span: zapSpanCore{
+				level: level,
+				span:  span,
+				with:  with,
+				opts:  o,
+				extra: extra,
+				state: state,
+			},
+		}
 	}
 
 	return logger.WithOptions(zap.WrapCore(wrap))
 }
 
 // SpanLoggerFromContext similar to SpanLogger but gets span from context.
-func SpanLoggerFromContext(ctx context.Context, logger *zap.Logger) *zap.Logger {
-	return SpanLogger(trace.SpanFromContext(ctx), logger)
+// The fields of the context extractor (see WithContextExtractor), if any,
+// are added to the logger even if there is no span.
+func SpanLoggerFromContext(ctx context.Context, logger *zap.Logger, opts ...Option) *zap.Logger {
+	o := newOptions(opts...)
+	logger = SpanLogger(trace.SpanFromContext(ctx), logger, o.debugOptions(ctx, opts)...)
+	if fields := o.contextFields(ctx); len(fields) != 0 {
+		logger = logger.With(fields...) // both log output and span events
+	}
+	return logger
+}
+
+// Unwrap gets the logger which does not write to the span,
+// e.g. for tight loops or background jobs.
+// If logger is not a span logger then the same logger returned.
+func Unwrap(logger *zap.Logger) *zap.Logger {
+	if _, ok := logger.Core().(spanTee); !ok {
+		return logger // not wrapped
+	}
+
+	unwrap := func(core zapcore.Core) zapcore.Core {
+		return core.(spanTee).core
+	}
+
+	return logger.WithOptions(zap.WrapCore(unwrap))
+}
+
+// EndSpanWithLogs writes all pending events of the span logger
+// (tail buffer, repeats, sampling and summary) and ends the span,
+// so a request can be finished in a single call.
+// Unlike WithTailBuffer alone, the buffered entries are written even
+// if no error occurred. The logger's own output is not synced.
+func EndSpanWithLogs(span trace.Span, logger *zap.Logger, opts ...trace.SpanEndOption) {
+	if tee, ok := logger.Core().(spanTee); ok && sameSpan(tee.span.span, span) {
+		if tee.span.opts.asyncQueue > 0 {
+			tee.span.state.async.wait()
+		}
+		if tee.span.opts.tailBuffer > 0 {
+			tee.span.flushBuffer()
+		}
+		_ = tee.span.Sync() // never fails
+	}
+
+	span.End(opts...)
+}
+
+// sameSpan checks if both spans are the same span.
+// Spans are compared by their span context since the span
+// implementation is not required to be comparable.
+func sameSpan(a, b trace.Span) bool {
+	ac, bc := a.SpanContext(), b.SpanContext()
+	if ac.HasSpanID() || bc.HasSpanID() {
+		return ac.TraceID() == bc.TraceID() && ac.SpanID() == bc.SpanID()
+	}
+
+	// no span identity, compare the spans themselves if possible
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// spanTee writes log entries to both the original core and the span.
+// Unlike zapcore.NewTee it makes the span core easy to find.
+type spanTee struct {
+	core zapcore.Core // the original core
+	span zapSpanCore
+}
+
+// Enabled checks if logging level is enabled.
+func (t spanTee) Enabled(level zapcore.Level) bool {
+	return t.core.Enabled(level) || t.span.Enabled(level)
+}
+
+// With adds structured context to the Core.
+func (t spanTee) With(fields []zapcore.Field) zapcore.Core {
+	return spanTee{
+		core: t.core.With(fields),
+		span: t.span.withFields(fields),
+	}
+}
+
+// Check determines whether the supplied Entry should be logged.
+func (t spanTee) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked = t.core.Check(entry, checked)
+	return t.span.Check(entry, checked)
+}
+
+// Write writes the Entry to both cores.
+func (t spanTee) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return multierr.Append(
+		t.core.Write(entry, fields),
+		t.span.Write(entry, fields))
+}
+
+// Sync flushes both cores.
+func (t spanTee) Sync() error {
+	return multierr.Append(
+		t.core.Sync(),
+		t.span.Sync())
 }
 
 // zapSpanCore writes log entries to the span as OpenTelemetry events.
 type zapSpanCore struct {
-	core zapcore.Core // actually is used to check levels
-	span trace.Span
-	with []zapcore.Field
+	level zapcore.LevelEnabler // span events threshold, the wrapped core by default
+	span  trace.Span
+	with  []zapcore.Field
+	opts  *options
+	// static attributes added to each event
+	extra []attribute.KeyValue
+	state *spanState
 }
 
 // Enabled checks if logging level is enabled.
 func (zs zapSpanCore) Enabled(level zapcore.Level) bool {
-	return zs.core.Enabled(level)
+	return zs.level.Enabled(level)
 }
 
 // With adds structured context to the Core.
 func (zs zapSpanCore) With(fields []zapcore.Field) zapcore.Core {
-	return zapSpanCore{
-		core: zs.core, // zs.core.With(fields), - no sense yet
-		span: zs.span,
-		with: concatFields(zs.with, fields),
-	}
+	return zs.withFields(fields)
+}
+
+// withFields adds structured context keeping the concrete type.
+func (zs zapSpanCore) withFields(fields []zapcore.Field) zapSpanCore {
+	out := zs
+	out.with = concatFields(zs.with, fields)
+	return out
 }
 
 // Check determines whether the supplied Entry should be logged.
 func (zs zapSpanCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
-	if zs.Enabled(entry.Level) {
-		checked = checked.AddCore(entry, zs)
+	if !zs.level.Enabled(entry.Level) {
+		return checked // span path adds no overhead
 	}
 
-	return checked
+	return checked.AddCore(entry, zs)
 }
 
 // Write serializes the Entry and any Fields supplied at the log site and
 // writes them to OpenTelemetry as an event.
 func (zs zapSpanCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
-	zs.span.AddEvent(entry.Message,
-		trace.WithAttributes(attributesFromZapFields(zs.with, fields,
-			attribute.Stringer("zap.level", entry.Level),
-			attribute.String("zap.logger_name", entry.LoggerName),
-		)...))
+	if zs.opts.eventFilter != nil && !zs.opts.eventFilter(entry, fields) {
+		return nil // filtered out
+	}
+	if zs.opts.summary {
+		zs.state.count(entry.Level)
+	}
+	if zs.opts.errorCount && entry.Level >= zapcore.ErrorLevel {
+		zs.state.countError(zs.span)
+	}
+	if zs.opts.warnings && entry.Level >= zapcore.WarnLevel {
+		zs.state.markWarnings(zs.span)
+	}
+	if zs.opts.firstError && entry.Level >= zapcore.ErrorLevel {
+		zs.captureFirstError(entry, fields)
+	}
+	if zs.opts.errStatus && entry.Level >= zapcore.ErrorLevel {
+		zs.span.SetStatus(codes.Error, entry.Message)
+	}
+	if len(zs.opts.metricHooks) != 0 {
+		zs.recordMetrics(entry, fields)
+	}
+	if zs.opts.sampleFirst > 0 && zs.opts.securitySeverity(entry, zs.with, fields) == 0 &&
+		!zs.state.sample(entry.Message, zs.opts.sampleFirst, zs.opts.sampleThereafter) {
+		return nil // suppressed
+	}
+	if zs.opts.recordError && entry.Level >= zapcore.ErrorLevel {
+		fields = zs.recordErrors(fields) // call site only
+	}
 
+	if zs.opts.asyncQueue > 0 {
+		fields = append([]zapcore.Field(nil), fields...) // caller may reuse
+		zs.state.async.push(bufferedEntry{core: zs, entry: entry, fields: fields}, zs.opts.asyncQueue)
+		return nil // see emit
+	}
+
+	zs.emit(entry, fields)
 	return nil
 }
 
+// emit writes the Entry to the span as an event, or buffers it.
+func (zs zapSpanCore) emit(entry zapcore.Entry, fields []zapcore.Field) {
+	if zs.opts.tailBuffer > 0 {
+		if entry.Level < zapcore.ErrorLevel {
+			zs.state.buffer(zs, entry, fields, zs.opts.tailBuffer)
+			return // postponed
+		}
+		zs.flushBuffer()
+	}
+
+	if zs.opts.entryTime {
+		zs.addEvent(entry, fields, trace.WithTimestamp(entry.Time))
+		return
+	}
+	zs.addEvent(entry, fields)
+}
+
+// addEvent writes the Entry and fields to the span as an event.
+func (zs zapSpanCore) addEvent(entry zapcore.Entry, fields []zapcore.Field, opts ...trace.EventOption) {
+	if len(fields) == 0 && len(zs.with) == 0 && len(opts) == 0 &&
+		entry.Stack == "" && zs.opts.staticExtras() {
+		ev := zs.state.extrasOnly(zs, entry)
+		zs.writeEvent(entry, nil, ev.attrs, ev.opts, nil)
+		return
+	}
+
+	if !zs.opts.reuseScratch() {
+		zs.writeEvent(entry, fields, zs.attributes(entry, fields, nil), nil, opts)
+		return
+	}
+
+	s := scratchPool.Get().(*attrsScratch)
+	s.opts = append(s.opts, opts...)
+	zs.writeEvent(entry, fields, zs.attributes(entry, fields, s.attrs), nil, s.opts)
+	s.put()
+}
+
+// writeEvent writes the event attributes to the span.
+// The cached options, if any, contain the attributes already.
+func (zs zapSpanCore) writeEvent(entry zapcore.Entry, fields []zapcore.Field, attrs []attribute.KeyValue, cached, opts []trace.EventOption) {
+	attrs, ok := zs.opts.checkTenant(attrs)
+	if !ok {
+		return // no tenant
+	}
+	if severity := zs.opts.securitySeverity(entry, zs.with, fields); severity != 0 {
+		attrs = append(attrs, securitySeverityKey.String(severity.String()))
+		zs.addEventAs(entry.Message, attrs, nil, opts) // never limited
+		return
+	}
+	if zs.opts.dedupWindow > 0 {
+		last, ok := zs.state.dedup(entry, attrs, zs.opts.dedupWindow)
+		zs.writeRepeats(last)
+		if !ok {
+			return // repeated
+		}
+	}
+	if zs.opts.maxEvents > 0 && !zs.state.allowEvent(zs.opts.maxEvents) {
+		return // truncated
+	}
+	zs.addEventAs(entry.Message, attrs, cached, opts)
+}
+
+// addEventAs names and writes the event to the span.
+func (zs zapSpanCore) addEventAs(message string, attrs []attribute.KeyValue, cached, opts []trace.EventOption) {
+	name, attrs := zs.opts.eventName(message, attrs)
+	if zs.opts.debug != nil {
+		zs.opts.debug.write(name, attrs)
+	}
+	if zs.opts.export != nil {
+		cfg := trace.NewEventConfig(opts...) // now if no timestamp
+		zs.opts.export.write(zs.span.SpanContext(), name, cfg.Timestamp(), attrs)
+	}
+	if cached == nil {
+		cached = append(opts, trace.WithAttributes(attrs...))
+	}
+	zs.opts.addEvent(zs.span, name, cached...)
+}
+
+// attributes converts the Entry and all the fields into event attributes.
+// The buffer, if any, is used to avoid allocations.
+func (zs zapSpanCore) attributes(entry zapcore.Entry, fields []zapcore.Field, buf []attribute.KeyValue) []attribute.KeyValue {
+	if n := zs.estimateEvent(fields); cap(buf) < n {
+		buf = make([]attribute.KeyValue, 0, n)
+	}
+	return zs.appendEvent(buf[:0], entry, fields)
+}
+
 // Sync flushes buffered logs.
 func (zs zapSpanCore) Sync() error {
-	return nil // nothing to sync
+	if zs.opts.asyncQueue > 0 {
+		zs.state.async.wait()
+		zs.state.async.flushDropped(zs.span)
+	}
+	if zs.opts.dedupWindow > 0 {
+		zs.flushDedup()
+	}
+	zs.flushSampling()
+	if zs.opts.maxEvents > 0 {
+		zs.flushTruncated()
+	}
+	if zs.opts.summary {
+		zs.flushSummary()
+	}
+	return nil
+}
+
+// appendLoggerScope appends the full dotted logger name and its leaf.
+func appendLoggerScope(attrs []attribute.KeyValue, name string) []attribute.KeyValue {
+	leaf := name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		leaf = name[i+1:]
+	}
+	return append(attrs,
+		attribute.String("log.logger", name),
+		attribute.String("log.logger.leaf", leaf))
 }
@@ -1,12 +1,18 @@
 package otelzap_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -29,6 +35,14 @@ func TestSpanLogger(t *testing.T) {
 		Return(true).
 		AnyTimes()
 
+	L1, _ = newJSONLogger()
+	span.EXPECT().
+		AddEvent("no name",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+			))
+	SpanLogger(span, L1).Info("no name") // no empty "zap.logger_name"
+
 	L2, buf2 := newJSONLogger()
 	L2 = L2.Named("my")
 	SL2 := SpanLogger(span, L2)
@@ -52,6 +66,1002 @@ func TestSpanLogger(t *testing.T) {
 	assert.Equal(t, `{"level":"info","msg":"my message","bar":"hello","baz":321,"foo":123}`, buf2.Stripped())
 }
 
+// parentSpan is a mocked span that also reports its parent.
+type parentSpan struct {
+	*MockedSpan
+	parent trace.SpanContext
+}
+
+// Parent returns the parent span context.
+func (s parentSpan) Parent() trace.SpanContext {
+	return s.parent
+}
+
+func TestSpanLoggerParentSpanID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	span := NewMockedSpan(ctrl)
+	span.EXPECT().
+		IsRecording().
+		Return(true).
+		AnyTimes()
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	})
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(parentSpan{MockedSpan: span, parent: parent}, L, WithParentSpanID())
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("parent.span_id", "0102030405060708"),
+				attribute.Int("foo", 123),
+			))
+	SL.Info("my message", zap.Int("foo", 123))
+
+	// no parent available
+	SL = SpanLogger(span, L, WithParentSpanID())
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+			))
+	SL.Info("my message")
+}
+
+func TestSpanLoggerJSONFields(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithJSONFields()).
+		With(zap.String("bar", "hello"))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("log.fields", `{"bar":"hello","foo":[1,2]}`),
+			))
+	SL.Info("my message", zap.Ints("foo", []int{1, 2}))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+			))
+	SpanLogger(span, L, WithJSONFields()).Info("my message")
+}
+
+func TestSpanLoggerFieldOrder(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithFieldOrder(FieldOrderCallSiteFirst)).
+		With(zap.String("bar", "hello"))
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.Int("foo", 123),
+				attribute.String("bar", "hello"),
+				attribute.String("zap.level", "info"),
+			))
+	SL.Info("my message", zap.Int("foo", 123))
+
+	SL = SpanLogger(span, L, WithFieldOrder(FieldOrderExtraLast)).
+		With(zap.String("bar", "hello"))
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("bar", "hello"),
+				attribute.Int("foo", 123),
+				attribute.String("zap.level", "info"),
+			))
+	SL.Info("my message", zap.Int("foo", 123))
+}
+
+func TestSpanLoggerOverride(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L).
+		With(zap.String("foo", "with"), zap.String("bar", "hello"))
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("bar", "hello"),
+				attribute.String("foo", "call-site"),
+			))
+	SL.Info("my message", zap.String("foo", "call-site"))
+}
+
+func TestSamplingHook(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := L.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewSamplerWithOptions(core, time.Hour, 1, 0,
+			zapcore.SamplerHook(SamplingHook(span)))
+	}))
+
+	span.EXPECT().SetAttributes(attribute.Int64("log.sampled_out_count", 1))
+	span.EXPECT().SetAttributes(attribute.Int64("log.sampled_out_count", 2))
+	SL.Info("my message") // sampled
+	SL.Info("my message") // dropped
+	SL.Info("my message") // dropped
+}
+
+func TestSpanLoggerEventSampling(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithEventSampling(2, 3))
+
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Times(3)
+	span.EXPECT().
+		AddEvent("other message", gomock.Any()).
+		Times(1)
+	for i := 0; i < 7; i++ {
+		SL.Info("my message") // 1, 2, 5 are written
+	}
+	SL.Info("other message")
+
+	span.EXPECT().
+		AddEvent("log.sampling",
+			trace.WithAttributes(
+				attribute.Int("log.suppressed_count", 4),
+			))
+	assert.NoError(t, SL.Sync())
+	assert.NoError(t, SL.Sync()) // nothing to flush
+}
+
+func TestSpanLoggerSummary(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithSummary())
+
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Times(4)
+	SL.Warn("my message")
+	SL.Info("my message")
+	SL.Error("my message")
+	SL.Info("my message")
+	SL.Debug("my message") // disabled
+
+	span.EXPECT().
+		AddEvent("log.summary",
+			trace.WithAttributes(
+				attribute.Int("info", 2),
+				attribute.Int("warn", 1),
+				attribute.Int("error", 1),
+			))
+	assert.NoError(t, SL.Sync())
+	assert.NoError(t, SL.Sync()) // nothing to flush
+}
+
+func TestSpanLoggerSummaryIsolated(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	var debug bytes.Buffer
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithSummary(), WithDebugWriter(&debug))
+
+	span.EXPECT().AddEvent("my message", gomock.Any())
+	SL.Info("my message")
+
+	// the span panics, the summary is isolated and printed
+	span.EXPECT().
+		AddEvent("log.summary", gomock.Any()).
+		Do(func(string, ...trace.EventOption) { panic("oops") })
+	assert.NotPanics(t, func() { _ = SL.Sync() })
+	assert.Contains(t, debug.String(), `span event "log.summary":`)
+}
+
+func TestSpanLoggerErrorCount(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithErrorCount())
+
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Times(3)
+	span.EXPECT().SetAttributes(attribute.Int("log.error_count", 1))
+	span.EXPECT().SetAttributes(attribute.Int("log.error_count", 2))
+	SL.Error("my message")
+	SL.Warn("my message")
+	SL.Error("my message")
+}
+
+func TestSpanLoggerWarningsFlag(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithWarningsFlag())
+
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Times(4)
+	span.EXPECT().SetAttributes(attribute.Bool("log.has_warnings", true)) // once
+	SL.Info("my message")
+	SL.Warn("my message")
+	SL.Error("my message")
+	SL.With(zap.Int("foo", 1)).Warn("my message")
+}
+
+func TestSpanLoggerFirstError(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L.WithOptions(zap.AddCaller()), WithFirstError("user", "code")).
+		With(zap.String("user", "bob"))
+
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Times(3)
+	span.EXPECT().SetAttributes(gomock.Any()).
+		Do(func(attrs ...attribute.KeyValue) {
+			if assert.Len(t, attrs, 4) {
+				assert.Equal(t, attribute.String("first_error.message", "my message"), attrs[0])
+				assert.Equal(t, attribute.Key("first_error.at"), attrs[1].Key)
+				assert.Contains(t, attrs[1].Value.AsString(), "/span_logger_test.go:")
+				assert.Equal(t, attribute.String("first_error.user", "bob"), attrs[2])
+				assert.Equal(t, attribute.Int("first_error.code", 42), attrs[3])
+			}
+		}) // once
+	SL.Warn("my message")
+	SL.Error("my message", zap.Int("code", 42))
+	SL.Error("my message", zap.Int("code", 43))
+}
+
+func TestSpanLoggerSpanLevel(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, buf := newJSONLogger() // Info
+	SL := SpanLogger(span, L, WithSpanLevel(zapcore.WarnLevel))
+
+	span.EXPECT().AddEvent("warn message", gomock.Any())
+	SL.Info("info message")
+	SL.Warn("warn message")
+	assert.Equal(t, []string{
+		`{"level":"info","msg":"info message"}`,
+		`{"level":"warn","msg":"warn message"}`,
+	}, buf.Lines())
+
+	// the reverse: debug events are not written to the console
+	buf.Reset()
+	SL = SpanLogger(span, L, WithSpanLevel(zapcore.DebugLevel))
+	span.EXPECT().AddEvent("debug message", gomock.Any())
+	SL.Debug("debug message")
+	assert.Empty(t, buf.Lines())
+}
+
+func TestSpanLoggerErrorStatus(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithErrorStatus())
+
+	span.EXPECT().
+		AddEvent(gomock.Any(), gomock.Any()).
+		Times(3)
+	span.EXPECT().SetStatus(codes.Error, "first failure")
+	span.EXPECT().SetStatus(codes.Error, "second failure")
+	SL.Warn("my message")
+	SL.Error("first failure")
+	SL.Error("second failure")
+}
+
+func TestSpanLoggerFlagCompaction(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithFlagCompaction()).
+		With(zap.Bool("cache_hit", true))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.Bool("dry_run", false),
+				attribute.Int("foo", 1),
+				attribute.StringSlice("flags", []string{"cache_hit", "retry"}),
+			))
+	SL.Info("my message", zap.Bool("dry_run", false), zap.Bool("retry", true), zap.Int("foo", 1))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.Int("foo", 1),
+			))
+	SpanLogger(span, L, WithFlagCompaction()).Info("my message", zap.Int("foo", 1))
+}
+
+func TestSpanLoggerRecordError(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithRecordError(false))
+
+	span.EXPECT().RecordError(assert.AnError)
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "error"),
+				attribute.String("error", assert.AnError.Error()),
+			))
+	SL.Error("my message", zap.Error(assert.AnError))
+
+	// not an error level
+	span.EXPECT().AddEvent("my message", gomock.Any())
+	SL.Warn("my message", zap.Error(assert.AnError))
+
+	// replace, bound errors are not recorded
+	SL = SpanLogger(span, L, WithRecordError(true)).
+		With(zap.NamedError("cause", context.Canceled))
+	span.EXPECT().RecordError(assert.AnError).Times(2)
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "error"),
+				attribute.String("cause", context.Canceled.Error()),
+				attribute.Int("foo", 1),
+			)).
+		Times(2)
+	SL.Error("my message", zap.Error(assert.AnError), zap.Int("foo", 1))
+	SL.Error("my message", zap.Error(assert.AnError), zap.Int("foo", 1))
+}
+
+// fixedClock is a zapcore.Clock returning the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                         { return time.Time(c) }
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+func TestSpanLoggerEntryTime(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	now := time.Date(2022, 12, 1, 10, 20, 30, 0, time.UTC)
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L.WithOptions(zap.WithClock(fixedClock(now))), WithEntryTime())
+
+	var events []trace.EventConfig
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Do(func(_ string, opts ...trace.EventOption) {
+			events = append(events, trace.NewEventConfig(opts...))
+		}).
+		Times(2)
+	SL.Info("my message")
+	SL.Info("my message", zap.Int("foo", 1))
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, now, events[0].Timestamp())
+		assert.Equal(t, now, events[1].Timestamp())
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("zap.level", "info"),
+			attribute.Int("foo", 1),
+		}, events[1].Attributes())
+	}
+}
+
+func TestSpanLoggerMaxEventBytesJSON(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithMaxEventBytes(80, OverflowJSON))
+
+	var events []trace.EventConfig
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Do(func(_ string, opts ...trace.EventOption) {
+			events = append(events, trace.NewEventConfig(opts...))
+		})
+	SL.Info("my message",
+		zap.String("a", "1234567890"),
+		zap.String("b", strings.Repeat("x", 100)))
+
+	if assert.Len(t, events, 1) {
+		attrs := events[0].Attributes()
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("zap.level", "info"),
+			attribute.String("log.fields", `{"a":"1234567890"}`),
+			attribute.Bool("otelzap.truncated", true),
+		}, attrs)
+		assert.True(t, json.Valid([]byte(attrs[1].Value.AsString())))
+	}
+}
+
+func TestSpanLoggerCallerAttributes(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L.WithOptions(zap.AddCaller()), WithCallerAttributes())
+
+	var events []trace.EventConfig
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Do(func(_ string, opts ...trace.EventOption) {
+			events = append(events, trace.NewEventConfig(opts...))
+		}).
+		Times(2)
+	SL.Info("my message")
+
+	// no caller
+	SpanLogger(span, L, WithCallerAttributes()).Info("my message")
+
+	if assert.Len(t, events, 2) {
+		attrs := events[0].Attributes()
+		if assert.Len(t, attrs, 4) {
+			assert.Equal(t, attribute.Key("code.filepath"), attrs[1].Key)
+			assert.True(t, strings.HasSuffix(attrs[1].Value.AsString(), "/span_logger_test.go"))
+			assert.Equal(t, attribute.Key("code.lineno"), attrs[2].Key)
+			assert.Positive(t, attrs[2].Value.AsInt64())
+			assert.Equal(t, attribute.String("code.function",
+				"github.com/Pilatuz/otelzap_test.TestSpanLoggerCallerAttributes"), attrs[3])
+		}
+		assert.Equal(t, []attribute.KeyValue{attribute.String("zap.level", "info")}, events[1].Attributes())
+	}
+}
+
+func TestSpanLoggerEntryStack(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L.WithOptions(zap.AddStacktrace(zapcore.ErrorLevel)))
+
+	var events []trace.EventConfig
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Do(func(_ string, opts ...trace.EventOption) {
+			events = append(events, trace.NewEventConfig(opts...))
+		}).
+		Times(3)
+	SL.Warn("my message") // no stack
+	SL.Error("my message")
+	SpanLogger(span, L.WithOptions(zap.AddStacktrace(zapcore.ErrorLevel)),
+		WithStackTrace(StackNone)).Error("my message")
+
+	if assert.Len(t, events, 3) {
+		assert.Len(t, events[0].Attributes(), 1)
+		attrs := events[1].Attributes()
+		if assert.Len(t, attrs, 2) {
+			assert.Equal(t, attribute.Key("code.stacktrace"), attrs[1].Key)
+			assert.Contains(t, attrs[1].Value.AsString(), "TestSpanLoggerEntryStack")
+		}
+		assert.Len(t, events[2].Attributes(), 1)
+	}
+}
+
+func TestSpanLoggerDebugTrigger(t *testing.T) {
+	span := newRecordingSpan(t)
+	ts, err := trace.ParseTraceState("debug=1")
+	assert.NoError(t, err)
+	span.EXPECT().
+		SpanContext().
+		Return(trace.NewSpanContext(trace.SpanContextConfig{TraceState: ts}))
+
+	L, buf := newJSONLogger() // Info
+	SL := SpanLogger(span, L, WithDebugTrigger("debug", "1"))
+	span.EXPECT().AddEvent("debug message", gomock.Any())
+	SL.Debug("debug message")
+	assert.Empty(t, buf.Lines())
+
+	// by baggage
+	span.EXPECT().
+		SpanContext().
+		Return(trace.SpanContext{}).
+		Times(2)
+	member, err := baggage.NewMember("debug", "1")
+	assert.NoError(t, err)
+	bag, err := baggage.New(member)
+	assert.NoError(t, err)
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	span.EXPECT().AddEvent("debug message", gomock.Any())
+	SpanLoggerFromContext(baggage.ContextWithBaggage(ctx, bag), L, WithDebugTrigger("debug", "1")).
+		Debug("debug message")
+
+	// not triggered
+	SpanLoggerFromContext(ctx, L, WithDebugTrigger("debug", "1")).
+		Debug("debug message")
+}
+
+func TestSpanLoggerTailBuffer(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithTailBuffer(2))
+
+	SL.Info("message 1")
+	SL.With(zap.Int("foo", 123)).Warn("message 2")
+	SL.Info("message 3")
+
+	ts := gomock.Any()
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("message 2",
+				ts,
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Int("foo", 123),
+				)),
+		span.EXPECT().
+			AddEvent("message 3",
+				ts,
+				trace.WithAttributes(
+					attribute.String("zap.level", "info"),
+				)),
+		span.EXPECT().
+			AddEvent("failure",
+				trace.WithAttributes(
+					attribute.String("zap.level", "error"),
+				)),
+	)
+	SL.Error("failure")
+}
+
+func TestSpanLoggerDedup(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithDedupWindow(time.Minute))
+
+	ts := gomock.Any()
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("retry",
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Int("attempt", 1),
+				)),
+		span.EXPECT().
+			AddEvent("retry",
+				ts,
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Int("attempt", 1),
+					attribute.Int("log.repeat_count", 2),
+				)),
+		span.EXPECT().
+			AddEvent("retry",
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Int("attempt", 2),
+				)),
+		span.EXPECT().
+			AddEvent("retry",
+				ts,
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Int("attempt", 2),
+					attribute.Int("log.repeat_count", 1),
+				)),
+	)
+	SL.Warn("retry", zap.Int("attempt", 1))
+	SL.Warn("retry", zap.Int("attempt", 1))
+	SL.Warn("retry", zap.Int("attempt", 1))
+	SL.Warn("retry", zap.Int("attempt", 2))
+	SL.Warn("retry", zap.Int("attempt", 2))
+	assert.NoError(t, SL.Sync())
+	assert.NoError(t, SL.Sync()) // nothing to flush
+}
+
+func TestEndSpanWithLogs(t *testing.T) {
+	span := newRecordingSpan(t)
+	span.EXPECT().SpanContext().Return(newSpanContext(1)).AnyTimes()
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithTailBuffer(2), WithSummary())
+	SL.Info("message 1")
+
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("message 1",
+				gomock.Any(),
+				trace.WithAttributes(
+					attribute.String("zap.level", "info"),
+				)),
+		span.EXPECT().
+			AddEvent("log.summary",
+				trace.WithAttributes(
+					attribute.Int("info", 1),
+				)),
+		span.EXPECT().End(),
+	)
+	EndSpanWithLogs(span, SL)
+
+	// not a span logger
+	span.EXPECT().End()
+	EndSpanWithLogs(span, L)
+}
+
+func TestEndSpanWithLogsUncomparableSpan(t *testing.T) {
+	mock := newRecordingSpan(t)
+	span1 := uncomparableSpan{MockedSpan: mock, sc: newSpanContext(1), tags: []string{"a"}}
+	span2 := uncomparableSpan{MockedSpan: mock, sc: newSpanContext(2), tags: []string{"b"}}
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span1, L, WithTailBuffer(2))
+	SL.Info("message 1")
+
+	// another span, nothing is flushed
+	mock.EXPECT().End()
+	EndSpanWithLogs(span2, SL)
+
+	gomock.InOrder(
+		mock.EXPECT().AddEvent("message 1", gomock.Any(), gomock.Any()),
+		mock.EXPECT().End(),
+	)
+	EndSpanWithLogs(span1, SL)
+}
+
+func TestSpanLoggerMaxEvents(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithMaxEvents(2))
+
+	span.EXPECT().
+		AddEvent("my message", gomock.Any()).
+		Times(2)
+	for i := 0; i < 5; i++ {
+		SL.Info("my message")
+	}
+
+	span.EXPECT().
+		AddEvent("log.events_truncated",
+			trace.WithAttributes(
+				attribute.Int("log.suppressed_count", 3),
+			))
+	assert.NoError(t, SL.Sync())
+	assert.NoError(t, SL.Sync()) // nothing to flush
+}
+
+func TestSpanLoggerEventNameNormalizer(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithEventNameNormalizer(nil))
+
+	span.EXPECT().
+		AddEvent("order * failed",
+			trace.WithAttributes(
+				attribute.String("zap.level", "error"),
+				attribute.Int("attempt", 2),
+				attribute.String("log.message", "order 123 failed"),
+			))
+	SL.Error("order 123 failed", zap.Int("attempt", 2))
+}
+
+func TestSpanLoggerLevelAttributes(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L,
+		WithLevelAttributes(zapcore.WarnLevel, attribute.Bool("notable", true)),
+		WithLevelAttributes(zapcore.ErrorLevel, attribute.Bool("alert", true)))
+
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("info",
+				trace.WithAttributes(
+					attribute.String("zap.level", "info"),
+				)),
+		span.EXPECT().
+			AddEvent("warn",
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.Bool("notable", true),
+				)),
+		span.EXPECT().
+			AddEvent("error",
+				trace.WithAttributes(
+					attribute.String("zap.level", "error"),
+					attribute.Bool("notable", true),
+					attribute.Bool("alert", true),
+					attribute.Int("foo", 123),
+				)),
+	)
+	SL.Info("info")
+	SL.Warn("warn")
+	SL.Error("error", zap.Int("foo", 123))
+}
+
+func TestSpanLoggerEventFilter(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, buf := newJSONLogger()
+	SL := SpanLogger(span, L, WithSummary(),
+		WithEventFilter(func(entry zapcore.Entry, fields []zapcore.Field) bool {
+			return entry.Message != "heartbeat"
+		}))
+
+	span.EXPECT().
+		AddEvent("handled", gomock.Any())
+	SL.Info("heartbeat")
+	SL.Info("handled")
+
+	span.EXPECT().
+		AddEvent("log.summary",
+			trace.WithAttributes(
+				attribute.Int("info", 1),
+			))
+	assert.NoError(t, SL.Sync())
+	assert.Equal(t, `{"level":"info","msg":"heartbeat"}`+"\n"+
+		`{"level":"info","msg":"handled"}`, buf.Stripped())
+}
+
+func TestSpanLoggerAsync(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithAsync(1))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("message 1", gomock.Any()).
+			Do(func(string, ...trace.EventOption) {
+				close(started)
+				<-release // block the worker
+			}),
+		span.EXPECT().
+			AddEvent("message 2",
+				trace.WithAttributes(
+					attribute.String("zap.level", "info"),
+					attribute.Int("foo", 123),
+				)),
+	)
+	SL.Info("message 1")
+	<-started
+	SL.Info("message 2", zap.Int("foo", 123)) // queued
+	SL.Info("message 3")                      // dropped
+	close(release)
+
+	span.EXPECT().
+		SetAttributes(attribute.Int("log.async.dropped_count", 1))
+	assert.NoError(t, SL.Sync())
+}
+
+func TestSpanLoggerAsyncBatch(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithAsync(4))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	gomock.InOrder(
+		span.EXPECT().
+			AddEvent("message 1", gomock.Any()).
+			Do(func(string, ...trace.EventOption) {
+				close(started)
+				<-release // block the worker
+			}),
+		span.EXPECT().
+			AddEvent("message 2",
+				trace.WithAttributes(
+					attribute.String("zap.level", "info"),
+					attribute.Int("foo", 1),
+				)),
+		span.EXPECT().
+			AddEvent("message 3",
+				trace.WithAttributes(
+					attribute.String("zap.level", "warn"),
+					attribute.String("bar", "hello"),
+					attribute.Int("foo", 2),
+				)),
+	)
+	SL.Info("message 1")
+	<-started
+	SL.Info("message 2", zap.Int("foo", 1)) // queued
+	SL.With(zap.String("bar", "hello")).Warn("message 3", zap.Int("foo", 2))
+	close(release) // both are written in bulk
+
+	assert.NoError(t, SL.Sync())
+}
+
+func TestSpanLoggerDeterministic(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L.Named("my"), WithDeterministic()).
+		With(zap.String("bar", "hello"))
+
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 6, time.FixedZone("X", 3600))
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("bar", "hello"),
+				attribute.String("time", "2023-01-02T02:04:05.000000006Z"),
+				attribute.String("zap.level", "info"),
+				attribute.String("zap.logger_name", "my"),
+			))
+	SL.Info("my message", zap.Time("time", ts))
+}
+
+func TestSpanLoggerLokiFields(t *testing.T) {
+	span := newRecordingSpan(t)
+	span.EXPECT().
+		SpanContext().
+		Return(trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		}))
+
+	L, buf := newJSONLogger()
+	SL := SpanLogger(span, L, WithLokiFields())
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+			))
+	SL.Info("my message")
+	assert.Equal(t, `{"level":"info","msg":"my message",`+
+		`"traceID":"0102030405060708090a0b0c0d0e0f10","spanID":"0102030405060708"}`, buf.Stripped())
+}
+
+func TestSpanLoggerNamespace(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithLoggerNamespace()).
+		With(zap.String("bar", "hello"))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("zap.logger_name", "payments"),
+				attribute.String("payments.bar", "hello"),
+				attribute.Int("payments.order_id", 123),
+			))
+	SL.Named("payments").Info("my message", zap.Int("order_id", 123))
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("bar", "hello"),
+			))
+	SL.Info("my message") // no name, no prefix
+}
+
+func TestSpanLoggerScope(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L, WithLoggerScope())
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("zap.logger_name", "svc.handler.db"),
+				attribute.String("log.logger", "svc.handler.db"),
+				attribute.String("log.logger.leaf", "db"),
+			))
+	SL.Named("svc").Named("handler").Named("db").Info("my message")
+
+	span.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("zap.logger_name", "svc"),
+				attribute.String("log.logger", "svc"),
+				attribute.String("log.logger.leaf", "svc"),
+			))
+	SL.Named("svc").Info("my message")
+}
+
+func TestSpanLoggerDisabledLevel(t *testing.T) {
+	span := newRecordingSpan(t)
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span, L).With(zap.String("foo", "bar"))
+
+	assert.Nil(t, SL.Check(zap.DebugLevel, "my message"))
+	fields := []zapcore.Field{zap.Int("baz", 123)}
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		SL.Debug("my message", fields...)
+	}))
+}
+
+func TestSpanLoggerDoubleWrap(t *testing.T) {
+	span1 := newRecordingSpan(t)
+	span1.EXPECT().SpanContext().Return(newSpanContext(1)).AnyTimes()
+	span2 := newRecordingSpan(t)
+	span2.EXPECT().SpanContext().Return(newSpanContext(2)).AnyTimes()
+
+	L, buf := newJSONLogger()
+	SL := SpanLogger(span1, L).With(zap.String("foo", "bar"))
+	assert.Same(t, SL, SpanLogger(span1, SL))
+
+	span1.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("foo", "bar"),
+			)) // exactly once
+	SL.Info("my message")
+
+	// another span replaces the first one
+	SL = SpanLogger(span2, SL)
+	span2.EXPECT().
+		AddEvent("my message",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("foo", "bar"),
+			))
+	SL.Info("my message")
+
+	assert.Equal(t, `{"level":"info","msg":"my message","foo":"bar"}`+"\n"+
+		`{"level":"info","msg":"my message","foo":"bar"}`, buf.Stripped())
+}
+
+// uncomparableSpan is a span which cannot be compared with ==.
+type uncomparableSpan struct {
+	*MockedSpan
+	sc   trace.SpanContext
+	tags []string
+}
+
+// SpanContext returns the span context.
+func (s uncomparableSpan) SpanContext() trace.SpanContext {
+	return s.sc
+}
+
+func TestSpanLoggerUncomparableSpan(t *testing.T) {
+	mock := newRecordingSpan(t)
+	span1 := uncomparableSpan{MockedSpan: mock, sc: newSpanContext(1), tags: []string{"a"}}
+	span2 := uncomparableSpan{MockedSpan: mock, sc: newSpanContext(2), tags: []string{"b"}}
+
+	L, _ := newJSONLogger()
+	SL := SpanLogger(span1, L)
+	assert.Same(t, SL, SpanLogger(span1, SL)) // no panic
+	assert.NotSame(t, SL, SpanLogger(span2, SL))
+}
+
+// newSpanContext creates a valid span context with the given span ID.
+func newSpanContext(id byte) trace.SpanContext {
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, id},
+	})
+}
+
+func TestUnwrap(t *testing.T) {
+	L, buf := newJSONLogger()
+	assert.Same(t, L, Unwrap(L))
+
+	span := newRecordingSpan(t) // no events expected
+	SL := SpanLogger(span, L).With(zap.String("foo", "bar"))
+	Unwrap(SL).Info("my message", zap.Int("baz", 123))
+
+	assert.Equal(t, `{"level":"info","msg":"my message","foo":"bar","baz":123}`, buf.Stripped())
+}
+
+// newRecordingSpan creates a new mocked span which is always recording.
+func newRecordingSpan(t *testing.T) *MockedSpan {
+	ctrl := gomock.NewController(t)
+	span := NewMockedSpan(ctrl)
+	span.EXPECT().
+		IsRecording().
+		Return(true).
+		AnyTimes()
+	return span
+}
+
 // newJSONLogger creates a new zap.Logger instance with a zaptest.Buffer as a writer.
 func newJSONLogger() (*zap.Logger, *zaptest.Buffer) {
 	encoder := zapcore.NewJSONEncoder(
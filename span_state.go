@@ -0,0 +1,41 @@
+package otelzap
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// spanState is a mutable state shared by all span cores
+// created by the same SpanLogger call.
+type spanState struct {
+	mu sync.Mutex
+
+	sampled    map[string]int // message -> number of events seen
+	suppressed int            // number of events suppressed by sampling
+
+	levels map[zapcore.Level]int // number of entries per level
+	errors int                   // number of Error+ entries
+	warned bool                  // "log.has_warnings" is set
+
+	firstError bool // the first error is captured
+
+	tail tailBuffer // recent entries waiting for an error
+
+	throttled map[attribute.Key]*throttledKey // state of throttled keys
+
+	last dedupEvent // the last written event
+
+	events    int // number of written events
+	truncated int // number of events exceeding the limit
+
+	async asyncQueue // entries waiting for the background worker
+
+	extras map[extrasKey]extrasEvent // cached events without fields
+}
+
+// newSpanState creates a new empty span state.
+func newSpanState() *spanState {
+	return &spanState{}
+}
@@ -0,0 +1,98 @@
+package otelzap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.uber.org/zap/zapcore"
+)
+
+// StackMode defines how stack traces are added to events.
+type StackMode int
+
+// Known stack modes.
+const (
+	// StackNone does not add any stack traces.
+	StackNone StackMode = iota
+
+	// StackRicher adds a single "exception.stacktrace" attribute
+	// with the richer one of the entry stack (see zap.AddStacktrace)
+	// and the stack of an error field (see pkg/errors).
+	StackRicher
+
+	// StackBoth adds the entry stack as "code.stacktrace" attribute
+	// and the stack of an error field as "exception.stacktrace" attribute.
+	StackBoth
+
+	// StackEntry adds the entry stack (see zap.AddStacktrace)
+	// as "code.stacktrace" attribute. This is the default.
+	StackEntry
+)
+
+// codeStacktraceKey is the attribute key of the entry stack.
+const codeStacktraceKey = attribute.Key("code.stacktrace")
+
+// appendStacks appends stack trace attributes according to the stack mode.
+func appendStacks(attrs []attribute.KeyValue, mode StackMode, entry zapcore.Entry, fields ...[]zapcore.Field) []attribute.KeyValue {
+	if mode == StackNone {
+		return attrs
+	}
+	if mode == StackEntry {
+		if entry.Stack != "" {
+			attrs = append(attrs, codeStacktraceKey.String(entry.Stack))
+		}
+		return attrs
+	}
+
+	var errStack string
+	for _, ff := range fields {
+		for _, f := range ff {
+			if f.Type != zapcore.ErrorType {
+				continue
+			}
+			if s := errorStack(f.Interface.(error)); s != "" {
+				errStack = s
+			}
+		}
+	}
+
+	if mode == StackBoth {
+		if entry.Stack != "" {
+			attrs = append(attrs, codeStacktraceKey.String(entry.Stack))
+		}
+		if errStack != "" {
+			attrs = append(attrs, semconv.ExceptionStacktraceKey.String(errStack))
+		}
+		return attrs
+	}
+
+	// prefer richer one
+	stack := entry.Stack
+	if strings.Count(errStack, "\n") >= strings.Count(stack, "\n") && errStack != "" {
+		stack = errStack
+	}
+	if stack != "" {
+		attrs = append(attrs, semconv.ExceptionStacktraceKey.String(stack))
+	}
+	return attrs
+}
+
+// errorStack gets the stack trace of an error if it has one.
+// The error should have `StackTrace()` method (see pkg/errors),
+// the deepest stack in the wrapped error chain is used.
+func errorStack(err error) (stack string) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		m := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		if s := fmt.Sprintf("%+v", m.Call(nil)[0].Interface()); s != "" {
+			stack = strings.TrimLeft(s, "\n")
+		}
+	}
+	return stack
+}
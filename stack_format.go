@@ -0,0 +1,219 @@
+package otelzap
+
+import (
+	"go/build"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// WithStackFrames limits the number of stack frames written to
+// the stack trace attributes (see WithStackTrace), the omitted frames
+// are replaced by a "... N more frames" line. Zero means unlimited.
+// This is useful for Panic entries which include huge stacks.
+func WithStackFrames(max int) Option {
+	return func(o *options) {
+		o.stackFrames = max
+	}
+}
+
+// WithStackSkipRuntime omits the Go runtime frames (e.g. "runtime.gopanic")
+// from the stack trace attributes.
+func WithStackSkipRuntime() Option {
+	return func(o *options) {
+		o.stackSkipRuntime = true
+	}
+}
+
+// WithStackTrimPaths strips the GOROOT and GOPATH prefixes from
+// the file paths of the stack trace attributes, e.g.
+// "/usr/local/go/src/net/http/server.go" becomes "net/http/server.go"
+// and "/home/me/go/pkg/mod/go.uber.org/zap@v1.24.0/logger.go"
+// becomes "go.uber.org/zap@v1.24.0/logger.go".
+func WithStackTrimPaths() Option {
+	return func(o *options) {
+		o.stackTrimPrefixes = append(o.stackTrimPrefixes, goPathPrefixes()...)
+	}
+}
+
+// WithStackElide omits the frames of the packages (e.g. vendored
+// dependencies and middleware layers) from the stack trace attributes,
+// keeping them focused on the application code. The prefixes are
+// matched against the function names, e.g. "github.com/gorilla/mux"
+// or "net/http.".
+func WithStackElide(prefixes ...string) Option {
+	return func(o *options) {
+		o.stackElide = append(o.stackElide, prefixes...)
+	}
+}
+
+// WithSourceRoot strips the source root directories or module paths
+// (e.g. "/home/ci/build" or "github.com/acme/app" for -trimpath builds)
+// from file paths of the stack trace and caller attributes, so they
+// contain stable relative paths across build environments.
+func WithSourceRoot(roots ...string) Option {
+	return func(o *options) {
+		for _, root := range roots {
+			root = filepath.ToSlash(root)
+			if root != "" && !strings.HasSuffix(root, "/") {
+				root += "/"
+			}
+			if root != "" {
+				o.sourceRoots = append(o.sourceRoots, root)
+			}
+		}
+	}
+}
+
+// trimSourcePath strips the source root, if any, from the file path.
+func (o *options) trimSourcePath(path string) string {
+	for _, root := range o.sourceRoots {
+		if strings.HasPrefix(path, root) {
+			return path[len(root):]
+		}
+	}
+	return path
+}
+
+// goPathPrefixes gets the GOROOT and GOPATH prefixes of the file paths.
+func goPathPrefixes() []string {
+	var prefixes []string
+	if root := runtime.GOROOT(); root != "" {
+		prefixes = append(prefixes, filepath.ToSlash(root)+"/src/")
+	}
+	for _, path := range filepath.SplitList(build.Default.GOPATH) {
+		prefixes = append(prefixes,
+			filepath.ToSlash(path)+"/pkg/mod/",
+			filepath.ToSlash(path)+"/src/")
+	}
+	return prefixes
+}
+
+// stackFrame is a parsed stack frame.
+type stackFrame struct {
+	function string
+	file     string // "path/to/file.go:line", may be empty
+}
+
+// needsStackFormat checks if the stack traces need to be formatted.
+func (o *options) needsStackFormat() bool {
+	return o.stackFrames > 0 || o.stackSkipRuntime ||
+		len(o.stackTrimPrefixes) != 0 || len(o.stackElide) != 0 ||
+		len(o.sourceRoots) != 0
+}
+
+// formatStacks formats the stack trace attributes in place.
+func (o *options) formatStacks(attrs []attribute.KeyValue) {
+	if !o.needsStackFormat() {
+		return // as is
+	}
+
+	for i, kv := range attrs {
+		if isStackKey(kv.Key) && kv.Value.Type() == attribute.STRING {
+			attrs[i].Value = attribute.StringValue(o.formatStack(kv.Value.AsString()))
+		}
+	}
+}
+
+// isStackKey checks if the attribute is a stack trace,
+// e.g. "exception.stacktrace" or "stacktrace" (see zap.Stack).
+func isStackKey(key attribute.Key) bool {
+	switch key {
+	case codeStacktraceKey, semconv.ExceptionStacktraceKey, "stacktrace":
+		return true
+	}
+	return strings.HasSuffix(string(key), ".stacktrace")
+}
+
+// formatStack filters, trims and limits the stack frames.
+func (o *options) formatStack(stack string) string {
+	frames := parseStack(stack)
+	out := frames[:0]
+	for _, frame := range frames {
+		if o.stackSkipRuntime && isRuntimeFrame(frame.function) {
+			continue // skip it
+		}
+		if o.elided(frame.function) {
+			continue // skip it
+		}
+		if file := o.trimSourcePath(frame.file); file != frame.file {
+			frame.file = file
+		} else {
+			for _, prefix := range o.stackTrimPrefixes {
+				if strings.HasPrefix(frame.file, prefix) {
+					frame.file = frame.file[len(prefix):]
+					break
+				}
+			}
+		}
+		out = append(out, frame)
+	}
+
+	omitted := 0
+	if o.stackFrames > 0 && len(out) > o.stackFrames {
+		omitted = len(out) - o.stackFrames
+		out = out[:o.stackFrames]
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(stack))
+	for i, frame := range out {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(frame.function)
+		if frame.file != "" {
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.file)
+		}
+	}
+	if omitted != 0 {
+		if len(out) != 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString("... ")
+		sb.WriteString(strconv.Itoa(omitted))
+		sb.WriteString(" more frames")
+	}
+	return sb.String()
+}
+
+// parseStack parses the stack in ZAP (and pkg/errors) format:
+//
+//	main.foo
+//		/path/to/main.go:12
+//	main.main
+//		/path/to/main.go:5
+func parseStack(stack string) []stackFrame {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	frames := make([]stackFrame, 0, (len(lines)+1)/2)
+	for i := 0; i < len(lines); i++ {
+		frame := stackFrame{function: lines[i]}
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			frame.file = strings.TrimPrefix(lines[i+1], "\t")
+			i++
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// elided checks if the function should be omitted.
+func (o *options) elided(function string) bool {
+	for _, prefix := range o.stackElide {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRuntimeFrame checks if the function belongs to the Go runtime.
+func isRuntimeFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.") ||
+		strings.HasPrefix(function, "runtime/")
+}
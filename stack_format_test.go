@@ -0,0 +1,65 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestFormatStack unit tests for stack formatting.
+func TestFormatStack(t *testing.T) {
+	stack := "main.foo\n\t/home/me/app/main.go:12\n" +
+		"runtime.gopanic\n\t/usr/local/go/src/runtime/panic.go:884\n" +
+		"net/http.HandlerFunc.ServeHTTP\n\t/usr/local/go/src/net/http/server.go:2109\n" +
+		"go.uber.org/zap.(*Logger).Panic\n\t/home/me/go/pkg/mod/go.uber.org/zap@v1.24.0/logger.go:258\n" +
+		"runtime.goexit\n\t/usr/local/go/src/runtime/asm_amd64.s:1598"
+
+	o := newOptions()
+	assert.False(t, o.needsStackFormat())
+	assert.Equal(t, stack, o.formatStack(stack))
+
+	o = newOptions(WithStackSkipRuntime())
+	o.stackTrimPrefixes = []string{"/usr/local/go/src/", "/home/me/go/pkg/mod/"}
+	assert.Equal(t, "main.foo\n\t/home/me/app/main.go:12\n"+
+		"net/http.HandlerFunc.ServeHTTP\n\tnet/http/server.go:2109\n"+
+		"go.uber.org/zap.(*Logger).Panic\n\tgo.uber.org/zap@v1.24.0/logger.go:258",
+		o.formatStack(stack))
+
+	o = newOptions(WithStackFrames(2))
+	assert.Equal(t, "main.foo\n\t/home/me/app/main.go:12\n"+
+		"runtime.gopanic\n\t/usr/local/go/src/runtime/panic.go:884\n"+
+		"... 3 more frames",
+		o.formatStack(stack))
+	assert.Equal(t, "a\nb", o.formatStack("a\nb")) // no files
+
+	attrs := []attribute.KeyValue{
+		attribute.String("code.stacktrace", stack),
+		attribute.String("other", stack),
+		attribute.String("stacktrace", stack),
+	}
+	newOptions(WithStackFrames(1)).formatStacks(attrs)
+	assert.Equal(t, "main.foo\n\t/home/me/app/main.go:12\n... 4 more frames", attrs[0].Value.AsString())
+	assert.Equal(t, stack, attrs[1].Value.AsString())
+	assert.Equal(t, attrs[0], attribute.String("code.stacktrace", attrs[2].Value.AsString()))
+
+	assert.NotEmpty(t, newOptions(WithStackTrimPaths()).stackTrimPrefixes)
+
+	o = newOptions(WithStackElide("net/http.", "go.uber.org/zap"))
+	assert.Equal(t, "main.foo\n\t/home/me/app/main.go:12\n"+
+		"runtime.gopanic\n\t/usr/local/go/src/runtime/panic.go:884\n"+
+		"runtime.goexit\n\t/usr/local/go/src/runtime/asm_amd64.s:1598",
+		o.formatStack(stack))
+
+	o = newOptions(WithSourceRoot("/home/me/app", "", "github.com/acme/app/"))
+	assert.Equal(t, []string{"/home/me/app/", "github.com/acme/app/"}, o.sourceRoots)
+	assert.Equal(t, "main.go:12", o.trimSourcePath("/home/me/app/main.go:12"))
+	assert.Equal(t, "pkg/x.go", o.trimSourcePath("github.com/acme/app/pkg/x.go"))
+	assert.Equal(t, "/other/main.go", o.trimSourcePath("/other/main.go"))
+	assert.Equal(t, "main.foo\n\tmain.go:12",
+		o.formatStack("main.foo\n\t/home/me/app/main.go:12"))
+
+	assert.True(t, isStackKey("stacktrace"))
+	assert.True(t, isStackKey("panic.stacktrace"))
+	assert.False(t, isStackKey("stacktraces"))
+}
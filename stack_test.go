@@ -0,0 +1,59 @@
+package otelzap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackError is used to check errors with stack trace.
+type stackError struct {
+	stack string
+}
+
+func (stackError) Error() string {
+	return "stack error"
+}
+
+func (e stackError) StackTrace() fmt.Stringer {
+	return Stringer{e.stack}
+}
+
+// TestErrorStack unit tests for errorStack function.
+func TestErrorStack(t *testing.T) {
+	assert.Empty(t, errorStack(nil))
+	assert.Empty(t, errorStack(assert.AnError))
+	assert.Equal(t, "a\nb", errorStack(stackError{"\na\nb"}))
+	assert.Equal(t, "inner", errorStack(fmt.Errorf("wrap: %w", stackError{"inner"})))
+}
+
+// TestAppendStacks unit tests for appendStacks function.
+func TestAppendStacks(t *testing.T) {
+	entry := zapcore.Entry{Stack: "entry\nstack\nhere"}
+	short := []zapcore.Field{zap.Error(stackError{"error\nstack"})}
+	long := []zapcore.Field{zap.Error(stackError{"error\nstack\nis\nlonger"})}
+
+	assert.Nil(t, appendStacks(nil, StackNone, entry, short))
+	assert.Nil(t, appendStacks(nil, StackRicher, zapcore.Entry{}))
+
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.String("exception.stacktrace", "entry\nstack\nhere")},
+		appendStacks(nil, StackRicher, entry, nil, short))
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.String("exception.stacktrace", "error\nstack\nis\nlonger")},
+		appendStacks(nil, StackRicher, entry, long, nil))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("code.stacktrace", "entry\nstack\nhere"),
+			attribute.String("exception.stacktrace", "error\nstack"),
+		},
+		appendStacks(nil, StackBoth, entry, short))
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.String("code.stacktrace", "entry\nstack\nhere")},
+		appendStacks(nil, StackEntry, entry, short))
+	assert.Nil(t, appendStacks(nil, StackEntry, zapcore.Entry{}, short))
+}
@@ -0,0 +1,70 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// summaryEventName is the name of summary event.
+const summaryEventName = "log.summary"
+
+// errorCountKey is the span attribute key of error entries count.
+const errorCountKey = attribute.Key("log.error_count")
+
+// hasWarningsKey is the span attribute key set if any Warn+ entry is written.
+const hasWarningsKey = attribute.Key("log.has_warnings")
+
+// count counts an entry of the level.
+func (st *spanState) count(level zapcore.Level) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.levels == nil {
+		st.levels = make(map[zapcore.Level]int)
+	}
+	st.levels[level]++
+}
+
+// flushSummary writes the number of entries per level as a summary event.
+func (zs zapSpanCore) flushSummary() {
+	st := zs.state
+	st.mu.Lock()
+	levels := st.levels
+	st.levels = nil
+	st.mu.Unlock()
+
+	if len(levels) == 0 {
+		return // nothing logged
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(levels))
+	for level := zapcore.DebugLevel; level <= zapcore.FatalLevel; level++ {
+		if n, ok := levels[level]; ok {
+			attrs = append(attrs, attribute.Int(level.String(), n))
+		}
+	}
+	zs.addEventAs(summaryEventName, attrs, nil, nil)
+}
+
+// countError counts an error entry and updates the span attribute.
+func (st *spanState) countError(span trace.Span) {
+	st.mu.Lock()
+	st.errors++
+	n := st.errors
+	st.mu.Unlock()
+
+	span.SetAttributes(errorCountKey.Int(n))
+}
+
+// markWarnings sets the span attribute on the first Warn+ entry.
+func (st *spanState) markWarnings(span trace.Span) {
+	st.mu.Lock()
+	marked := st.warned
+	st.warned = true
+	st.mu.Unlock()
+
+	if !marked {
+		span.SetAttributes(hasWarningsKey.Bool(true))
+	}
+}
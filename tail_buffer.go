@@ -0,0 +1,65 @@
+package otelzap
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// bufferedEntry is an entry waiting in the tail buffer or async queue.
+type bufferedEntry struct {
+	core   zapSpanCore
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// tailBuffer is a ring buffer of recent entries.
+type tailBuffer struct {
+	items []bufferedEntry
+	next  int // index of the next item to overwrite when full
+}
+
+// push adds an entry to the buffer, the oldest entry is dropped if full.
+func (tb *tailBuffer) push(item bufferedEntry, size int) {
+	if len(tb.items) < size {
+		tb.items = append(tb.items, item)
+		return
+	}
+
+	tb.items[tb.next] = item
+	tb.next = (tb.next + 1) % len(tb.items)
+}
+
+// drain gets all the buffered entries in order and resets the buffer.
+func (tb *tailBuffer) drain() []bufferedEntry {
+	items := make([]bufferedEntry, 0, len(tb.items))
+	items = append(items, tb.items[tb.next:]...)
+	items = append(items, tb.items[:tb.next]...)
+	tb.items = nil
+	tb.next = 0
+	return items
+}
+
+// buffer saves the entry in the tail buffer.
+func (st *spanState) buffer(core zapSpanCore, entry zapcore.Entry, fields []zapcore.Field, size int) {
+	item := bufferedEntry{
+		core:   core,
+		entry:  entry,
+		fields: append([]zapcore.Field(nil), fields...),
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.tail.push(item, size)
+}
+
+// drainBuffer gets and resets all the entries from the tail buffer.
+func (st *spanState) drainBuffer() []bufferedEntry {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.tail.drain()
+}
+
+// flushBuffer writes all the buffered entries as events
+// using original entry time as event timestamps.
+func (zs zapSpanCore) flushBuffer() {
+	addEvents(zs.state.drainBuffer(), true)
+}
@@ -0,0 +1,35 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestTailBuffer unit tests for tailBuffer.
+func TestTailBuffer(t *testing.T) {
+	var tb tailBuffer
+	assert.Empty(t, tb.drain())
+
+	push := func(msg string) {
+		tb.push(bufferedEntry{entry: zapcore.Entry{Message: msg}}, 3)
+	}
+	messages := func(items []bufferedEntry) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.entry.Message)
+		}
+		return out
+	}
+
+	push("1")
+	push("2")
+	assert.Equal(t, []string{"1", "2"}, messages(tb.drain()))
+	assert.Empty(t, tb.drain())
+
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		push(msg)
+	}
+	assert.Equal(t, []string{"3", "4", "5"}, messages(tb.drain()))
+}
@@ -0,0 +1,69 @@
+package otelzap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TenantPolicy defines what to do with events missing the tenant attribute.
+type TenantPolicy int
+
+// Known tenant policies.
+const (
+	// TenantFlag writes the event with "log.tenant_missing" attribute.
+	TenantFlag TenantPolicy = iota
+
+	// TenantDrop drops the event.
+	TenantDrop
+)
+
+// tenantMissingKey is the attribute key which flags events missing tenant.
+const tenantMissingKey = attribute.Key("log.tenant_missing")
+
+// WithRequiredTenant requires the tenant (or partition) attribute on every
+// span event, enforcing per-tenant observability isolation. The events
+// missing the attribute are flagged or dropped according to the policy.
+//
+// SpanLoggerFromContext and Middleware take the tenant from the context
+// baggage member with the same key, if the context extractor
+// (see WithContextExtractor) does not provide it.
+func WithRequiredTenant(key string, policy TenantPolicy) Option {
+	return func(o *options) {
+		o.tenantKey = attribute.Key(key)
+		o.tenantPolicy = policy
+	}
+}
+
+// appendTenantField appends the tenant field from the context baggage,
+// if it is not provided yet.
+func (o *options) appendTenantField(ctx context.Context, fields []zapcore.Field) []zapcore.Field {
+	if o.tenantKey == "" || hasFieldKey(fields, string(o.tenantKey)) {
+		return fields // disabled or already provided
+	}
+
+	if value := baggage.FromContext(ctx).Member(string(o.tenantKey)).Value(); value != "" {
+		fields = append(fields[:len(fields):len(fields)], // copy
+			zap.String(string(o.tenantKey), value))
+	}
+	return fields
+}
+
+// checkTenant checks if the event has the tenant attribute.
+// Returns false if the event should be dropped.
+func (o *options) checkTenant(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	if o.tenantKey == "" {
+		return attrs, true // disabled
+	}
+	if _, ok := findAttribute(attrs, o.tenantKey); ok {
+		return attrs, true // found
+	}
+
+	if o.tenantPolicy == TenantDrop {
+		return attrs, false
+	}
+	return append(attrs, tenantMissingKey.Bool(true)), true
+}
@@ -0,0 +1,63 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestTenantFields unit tests for tenant from context baggage.
+func TestTenantFields(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	assert.Empty(t, newOptions().contextFields(ctx))
+
+	o := newOptions(WithRequiredTenant("tenant", TenantFlag))
+	assert.Equal(t, []zapcore.Field{zap.String("tenant", "acme")}, o.contextFields(ctx))
+	assert.Empty(t, o.contextFields(context.Background()))
+
+	// provided by extractor
+	o = newOptions(
+		WithRequiredTenant("tenant", TenantFlag),
+		WithContextExtractor(func(context.Context) []zapcore.Field {
+			return []zapcore.Field{zap.String("tenant", "other")}
+		}))
+	assert.Equal(t, []zapcore.Field{zap.String("tenant", "other")}, o.contextFields(ctx))
+}
+
+// TestCheckTenant unit tests for tenant enforcement.
+func TestCheckTenant(t *testing.T) {
+	with := []attribute.KeyValue{attribute.String("tenant", "acme")}
+	without := []attribute.KeyValue{attribute.String("foo", "bar")}
+
+	attrs, ok := newOptions().checkTenant(without)
+	assert.True(t, ok)
+	assert.Equal(t, without, attrs)
+
+	o := newOptions(WithRequiredTenant("tenant", TenantFlag))
+	attrs, ok = o.checkTenant(with)
+	assert.True(t, ok)
+	assert.Equal(t, with, attrs)
+	attrs, ok = o.checkTenant(without)
+	assert.True(t, ok)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("foo", "bar"),
+		attribute.Bool("log.tenant_missing", true),
+	}, attrs)
+
+	o = newOptions(WithRequiredTenant("tenant", TenantDrop))
+	_, ok = o.checkTenant(with)
+	assert.True(t, ok)
+	_, ok = o.checkTenant(without)
+	assert.False(t, ok)
+}
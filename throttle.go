@@ -0,0 +1,52 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// throttledKey is the state of a throttled attribute key.
+type throttledKey struct {
+	seen int             // number of occurrences
+	last attribute.Value // last attached value
+}
+
+// throttle removes the throttled attributes which should not be attached:
+// only every Nth occurrence is attached or, if N is not positive,
+// only the changed values are attached.
+func (st *spanState) throttle(attrs []attribute.KeyValue, every map[attribute.Key]int) []attribute.KeyValue {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.throttled == nil {
+		st.throttled = make(map[attribute.Key]*throttledKey)
+	}
+
+	out := attrs[:0:0] // do not modify the original
+	for _, attr := range attrs {
+		n, ok := every[attr.Key]
+		if !ok {
+			out = append(out, attr)
+			continue // not throttled
+		}
+
+		tk := st.throttled[attr.Key]
+		if tk == nil {
+			tk = &throttledKey{}
+			st.throttled[attr.Key] = tk
+		}
+		tk.seen++
+
+		if n > 0 {
+			if (tk.seen-1)%n != 0 {
+				continue // skip
+			}
+		} else if tk.seen > 1 && tk.last == attr.Value {
+			continue // not changed
+		}
+
+		tk.last = attr.Value
+		out = append(out, attr)
+	}
+
+	return out
+}
@@ -0,0 +1,41 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestThrottle unit tests for spanState.throttle method.
+func TestThrottle(t *testing.T) {
+	st := newSpanState()
+	every := map[attribute.Key]int{"progress": 2, "offset": 0}
+
+	attrs := func(progress, offset int) []attribute.KeyValue {
+		return []attribute.KeyValue{
+			attribute.Int("progress", progress),
+			attribute.Int("offset", offset),
+			attribute.String("foo", "bar"),
+		}
+	}
+
+	assert.Equal(t,
+		attrs(1, 10),
+		st.throttle(attrs(1, 10), every))
+	assert.Equal(t,
+		[]attribute.KeyValue{attribute.String("foo", "bar")},
+		st.throttle(attrs(2, 10), every))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Int("progress", 3),
+			attribute.String("foo", "bar"),
+		},
+		st.throttle(attrs(3, 10), every))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Int("offset", 20),
+			attribute.String("foo", "bar"),
+		},
+		st.throttle(attrs(4, 20), every))
+}
@@ -0,0 +1,51 @@
+package otelzap
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Transport creates HTTP client transport which logs the outbound request
+// and response summaries (see HTTPRequest and HTTPResponse) via the span
+// logger bound to the client span in the request context, e.g. started by
+// otelhttp.Transport. If base is nil then http.DefaultTransport is used.
+func Transport(base http.RoundTripper, logger *zap.Logger, opts ...Option) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{
+		base:   base,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// transport logs outbound HTTP requests.
+type transport struct {
+	base   http.RoundTripper
+	logger *zap.Logger
+	opts   []Option
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(r)
+	elapsed := time.Since(start)
+
+	log := SpanLoggerFromContext(r.Context(), t.logger, t.opts...)
+	fields := HTTPRequest(r, trace.SpanKindClient)
+	if err != nil {
+		log.Error("HTTP request failed", append(fields, zap.Error(err))...)
+		return resp, err
+	}
+
+	if ce := log.Check(levelFromHTTP(resp.StatusCode), "HTTP request completed"); ce != nil {
+		ce.Write(append(fields, HTTPResponse(resp.StatusCode, elapsed, trace.SpanKindClient)...)...)
+	}
+	return resp, nil
+}
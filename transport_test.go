@@ -0,0 +1,64 @@
+package otelzap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	span := newRecordingSpan(t)
+	span.EXPECT().
+		AddEvent("HTTP request completed", gomock.Any())
+
+	L, buf := newJSONLogger()
+	client := &http.Client{Transport: Transport(nil, L)}
+
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/users", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Contains(t, buf.String(), `"level":"warn","msg":"HTTP request completed"`)
+	assert.Contains(t, buf.String(), `"http.url":"`+srv.URL+`/users"`)
+	assert.Contains(t, buf.String(), `"http.status_code":404`)
+	assert.Contains(t, buf.String(), `"http.client.duration":`)
+}
+
+func TestTransportError(t *testing.T) {
+	L, buf := newJSONLogger()
+	failing := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, assert.AnError
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	require.NoError(t, err)
+	_, err = Transport(failing, L.With(zap.String("foo", "bar"))).RoundTrip(req)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	assert.Contains(t, buf.String(), `"level":"error","msg":"HTTP request failed","foo":"bar"`)
+	assert.Contains(t, buf.String(), `"error":"assert.AnError general error for testing"`)
+}
+
+// roundTripperFunc is a function implementing http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
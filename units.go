@@ -0,0 +1,35 @@
+package otelzap
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// unitSuffix is the key suffix of the unit attributes.
+const unitSuffix = ".unit"
+
+// WithUnits associates units with attribute keys (e.g. "latency": "ms").
+// The unit is written as the companion "<key>.unit" attribute,
+// so downstream analytics interpret numeric attributes correctly.
+// UCUM unit codes (e.g. "ms", "By", "1") are recommended.
+// Can be used multiple times, the later units override.
+func WithUnits(units map[string]string) Option {
+	return func(o *options) {
+		if o.units == nil {
+			o.units = make(map[string]string, len(units))
+		}
+		for key, unit := range units {
+			o.units[key] = unit
+		}
+	}
+}
+
+// appendUnits appends the unit attributes of the converted attributes.
+func (o *options) appendUnits(attributes []attribute.KeyValue, from int) []attribute.KeyValue {
+	for i, n := from, len(attributes); i < n; i++ {
+		key := string(attributes[i].Key)
+		if unit, ok := o.units[key]; ok {
+			attributes = append(attributes, attribute.String(key+unitSuffix, unit))
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,38 @@
+package otelzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// TestUnits unit tests for WithUnits option.
+func TestUnits(t *testing.T) {
+	o := newOptions(
+		WithUnits(map[string]string{"latency": "s", "size": "By"}),
+		WithUnits(map[string]string{"latency": "ms"}),
+		WithKeyRenames(map[string]string{"bytes": "size"}))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Int("latency", 15),
+			attribute.String("latency.unit", "ms"),
+			attribute.Int("size", 1024),
+			attribute.String("size.unit", "By"),
+			attribute.String("foo", "bar"),
+		},
+		o.appendZapFields(nil,
+			zap.Int("latency", 15),
+			zap.Int("bytes", 1024),
+			zap.String("foo", "bar")))
+
+	o = newOptions(WithUnits(map[string]string{"timeout": "ns"}))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.Int64("timeout", time.Second.Nanoseconds()),
+			attribute.String("timeout.unit", "ns"),
+		},
+		o.appendZapFields(nil, zap.Int64("timeout", time.Second.Nanoseconds())))
+}
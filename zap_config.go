@@ -0,0 +1,173 @@
+package otelzap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrStatefulOption is returned by NewLoggerFromZapConfig
+// if an option needs the span state.
+var ErrStatefulOption = errors.New("otelzap: option needs span state")
+
+// contextValue is the value of the Context field.
+type contextValue struct {
+	ctx context.Context
+}
+
+// Context creates a field carrying the context, so the span core
+// (see NewLoggerFromZapConfig) writes the entry to the context span.
+// The field is not encoded to log output.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Type: zapcore.SkipType, Interface: contextValue{ctx: ctx}}
+}
+
+// contextFromFields gets the last context of the fields, if any.
+func contextFromFields(fields []zapcore.Field) (context.Context, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Type != zapcore.SkipType {
+			continue
+		}
+		if cv, ok := fields[i].Interface.(contextValue); ok && cv.ctx != nil {
+			return cv.ctx, true
+		}
+	}
+	return nil, false
+}
+
+// NewLoggerFromZapConfig builds the logger from the zap configuration
+// and pre-wires the span core, so each entry with the Context field
+// (either bound with logger.With() or provided at the call site)
+// is also written to the context span:
+//
+//	logger, err := otelzap.NewLoggerFromZapConfig(zap.NewProductionConfig())
+//	...
+//	logger.Info("hello", otelzap.Context(ctx))
+//
+// The trace fields (see WithProfile) and the context extractor fields
+// (see WithContextExtractor) are added to log output by logger.With() only.
+// Since there is no span logger per span, the options which need
+// the span state (sampling, summary, deduplication, etc.) are rejected
+// with ErrStatefulOption.
+func NewLoggerFromZapConfig(cfg zap.Config, opts ...Option) (*zap.Logger, error) {
+	o := newOptions(opts...)
+	if names := o.statefulOptions(); len(names) != 0 {
+		return nil, fmt.Errorf("%w: %s", ErrStatefulOption, strings.Join(names, ", "))
+	}
+	wrap := func(core zapcore.Core) zapcore.Core {
+		return contextCore{core: core, opts: o}
+	}
+	return cfg.Build(zap.WrapCore(wrap))
+}
+
+// contextCore writes log entries to the span of the Context field.
+type contextCore struct {
+	core zapcore.Core // the original core
+	with []zapcore.Field
+	opts *options
+}
+
+// Enabled checks if logging level is enabled.
+func (c contextCore) Enabled(level zapcore.Level) bool {
+	return c.core.Enabled(level) || c.opts.spanLevelOr(c.core).Enabled(level)
+}
+
+// With adds structured context to the Core.
+func (c contextCore) With(fields []zapcore.Field) zapcore.Core {
+	core := c.core
+	if ctx, ok := contextFromFields(fields); ok {
+		extra := c.opts.contextFields(ctx)
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			extra = append(extra, c.opts.traceFields(span)...)
+		}
+		if len(extra) != 0 {
+			core = core.With(extra) // log output only
+		}
+	}
+
+	return contextCore{
+		core: core.With(fields),
+		with: concatFields(c.with, fields),
+		opts: c.opts,
+	}
+}
+
+// Check determines whether the supplied Entry should be logged.
+func (c contextCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked = c.core.Check(entry, checked)
+	if !c.opts.spanLevelOr(c.core).Enabled(entry.Level) {
+		return checked // span path adds no overhead
+	}
+
+	return checked.AddCore(entry, contextSpanCore{c})
+}
+
+// Write writes the Entry to the original core.
+func (c contextCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.core.Write(entry, fields)
+}
+
+// Sync flushes the original core.
+func (c contextCore) Sync() error {
+	return c.core.Sync()
+}
+
+// contextSpanCore writes log entries to the context span only.
+type contextSpanCore struct {
+	contextCore
+}
+
+// Write writes the Entry to the context span, if any.
+func (c contextSpanCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ctx, ok := contextFromFields(fields)
+	if !ok {
+		if ctx, ok = contextFromFields(c.with); !ok {
+			return nil // no context
+		}
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return nil // no tracing enabled
+	}
+
+	zs := zapSpanCore{
+		level: c.opts.spanLevelOr(c.core),
+		span:  span,
+		with:  concatFields(c.opts.contextFields(ctx), c.with),
+		opts:  c.opts,
+		extra: c.opts.spanAttributes(span),
+		state: newSpanState(),
+	}
+	return zs.Write(entry, fields) // nothing to sync, no stateful options
+}
+
+// Sync does nothing, see Write.
+func (c contextSpanCore) Sync() error {
+	return nil
+}
+
+// statefulOptions gets the names of the options which need the span state.
+func (o *options) statefulOptions() []string {
+	var names []string
+	add := func(set bool, name string) {
+		if set {
+			names = append(names, name)
+		}
+	}
+	add(o.tailBuffer > 0, "WithTailBuffer")
+	add(o.summary, "WithSummary")
+	add(o.errorCount, "WithErrorCount")
+	add(o.warnings, "WithWarningsFlag")
+	add(o.firstError, "WithFirstError")
+	add(o.sampleFirst > 0, "WithEventSampling")
+	add(len(o.throttle) != 0, "WithKeyThrottling")
+	add(o.dedupWindow > 0, "WithDedupWindow")
+	add(o.maxEvents > 0, "WithMaxEvents")
+	add(o.asyncQueue > 0, "WithAsync")
+	return names
+}
@@ -0,0 +1,65 @@
+package otelzap_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/Pilatuz/otelzap"
+)
+
+func TestNewLoggerFromZapConfig(t *testing.T) {
+	span := newRecordingSpan(t)
+	span.EXPECT().SpanContext().Return(trace.SpanContext{}).AnyTimes()
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "log.json")}
+	cfg.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	L, err := NewLoggerFromZapConfig(cfg, WithContextExtractor(func(ctx context.Context) []zapcore.Field {
+		return []zapcore.Field{zap.String("user", "bob")}
+	}))
+	require.NoError(t, err)
+
+	span.EXPECT().
+		AddEvent("call site",
+			trace.WithAttributes(
+				attribute.String("zap.level", "info"),
+				attribute.String("user", "bob"),
+				attribute.Int("foo", 123),
+			))
+	L.Info("call site", Context(ctx), zap.Int("foo", 123))
+
+	span.EXPECT().
+		AddEvent("bound",
+			trace.WithAttributes(
+				attribute.String("zap.level", "warn"),
+				attribute.String("user", "bob"),
+				attribute.String("bar", "baz"),
+			))
+	L.With(Context(ctx), zap.String("bar", "baz")).Warn("bound")
+
+	L.Info("no context")
+	L.Info("no span", Context(context.Background()))
+	L.Debug("disabled", Context(ctx))
+
+	// context field is not encoded
+	enc := zapcore.NewMapObjectEncoder()
+	Context(ctx).AddTo(enc)
+	assert.Empty(t, enc.Fields)
+}
+
+func TestNewLoggerFromZapConfigStateful(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{filepath.Join(t.TempDir(), "log.json")}
+	_, err := NewLoggerFromZapConfig(cfg, WithSummary(), WithEventSampling(1, 10), WithParentSpanID())
+	assert.ErrorIs(t, err, ErrStatefulOption)
+	assert.EqualError(t, err, "otelzap: option needs span state: WithSummary, WithEventSampling")
+}
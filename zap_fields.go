@@ -1,18 +1,20 @@
 package otelzap
 
 import (
-	"bytes"
 	"encoding"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math"
-	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -21,6 +23,16 @@ func attributesFromZapFields(
 	with []zapcore.Field,
 	fields []zapcore.Field,
 	extra ...attribute.KeyValue,
+) []attribute.KeyValue {
+	return defaultOptions.attributesFromZapFields(with, fields, extra...)
+}
+
+// attributesFromZapFields converts multiple ZAP fields into OpenTelemetry attributes
+// using the options.
+func (o *options) attributesFromZapFields(
+	with []zapcore.Field,
+	fields []zapcore.Field,
+	extra ...attribute.KeyValue,
 ) []attribute.KeyValue {
 	if len(with)+len(fields) == 0 {
 		// no fields, use extra attributes only
@@ -28,14 +40,34 @@ func attributesFromZapFields(
 	}
 
 	// convert each ZAP field...
-	attrs := make([]attribute.KeyValue, 0, len(with)+len(fields)+len(extra))
+	attrs := make([]attribute.KeyValue, 0, EstimateAttrs(with, fields)+len(extra))
 	attrs = append(attrs, extra...) // use extra "as is"
-	attrs = AppendZapFields(attrs, with...)
-	attrs = AppendZapFields(attrs, fields...)
+	attrs = o.appendZapFields(attrs, with...)
+	attrs = o.appendZapFields(attrs, fields...)
 
 	return attrs
 }
 
+// EstimateAttrs estimates the number of attributes the ZAP fields are converted to,
+// so the attribute slice can be sized right the first time (see AppendZapFields).
+// The skipped fields (see zap.Skip and zap.Namespace) are not counted.
+func EstimateAttrs(with []zapcore.Field, fields []zapcore.Field) int {
+	return estimateAttrs(with) + estimateAttrs(fields)
+}
+
+// estimateAttrs estimates the number of attributes of a few ZAP fields.
+func estimateAttrs(fields []zapcore.Field) int {
+	n := 0
+	for _, field := range fields {
+		switch field.Type {
+		case zapcore.SkipType, zapcore.NamespaceType:
+			continue // skipped
+		}
+		n++
+	}
+	return n
+}
+
 // AppendZapFields converts and appends a few ZAP fields.
 func AppendZapFields(attributes []attribute.KeyValue, fields ...zapcore.Field) []attribute.KeyValue {
 	for _, field := range fields {
@@ -44,6 +76,97 @@ func AppendZapFields(attributes []attribute.KeyValue, fields ...zapcore.Field) [
 	return attributes
 }
 
+// appendZapFields converts and appends a few ZAP fields using the options.
+func (o *options) appendZapFields(attributes []attribute.KeyValue, fields ...zapcore.Field) []attribute.KeyValue {
+	for _, field := range fields {
+		n := len(attributes)
+		attributes = o.appendZapField(attributes, field)
+		if o.omitZero {
+			attributes = omitZero(attributes, n)
+		}
+	}
+	return attributes
+}
+
+// appendZapField converts and appends a ZAP field using the options.
+func (o *options) appendZapField(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	if o.units == nil && o.enums == nil {
+		return o.internZapField(attributes, field)
+	}
+
+	n := len(attributes)
+	attributes = o.internZapField(attributes, field)
+	if o.enums != nil {
+		attributes = o.normalizeEnums(attributes, n)
+	}
+	if o.units != nil {
+		attributes = o.appendUnits(attributes, n)
+	}
+	return attributes
+}
+
+// internZapField converts the field interning the attributes, if enabled.
+func (o *options) internZapField(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	if o.interner == nil {
+		return o.convertZapField(attributes, field)
+	}
+	if field.Type == zapcore.ByteStringType && o.keyRenames == nil && !o.hashKeys[field.Key] {
+		return o.interner.appendByteString(attributes, field)
+	}
+
+	n := len(attributes)
+	attributes = o.convertZapField(attributes, field)
+	o.interner.internAttributes(attributes[n:])
+	return attributes
+}
+
+// convertZapField converts and appends a ZAP field using the options.
+func (o *options) convertZapField(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
+	isError := field.Type == zapcore.ErrorType &&
+		(o.exceptionAttrs || o.errorAsException && field.Key == "error")
+	if key, ok := o.keyRenames[field.Key]; ok {
+		field.Key = key
+	}
+	if o.hashKeys[field.Key] {
+		return appendHashed(attributes, field)
+	}
+
+	if isError {
+		if !o.errorRename && !o.exceptionAttrs {
+			attributes = appendZapField(attributes, field) // keep original
+		}
+		return appendException(attributes, field.Interface.(error))
+	}
+
+	if o.deterministic {
+		switch field.Type {
+		case zapcore.TimeType: // see zap.Time()
+			t := time.Unix(0, field.Integer).UTC()
+			return append(attributes, attribute.String(field.Key, t.Format(time.RFC3339Nano)))
+		case zapcore.TimeFullType: // see zap.Time()
+			t := field.Interface.(time.Time).UTC()
+			return append(attributes, attribute.String(field.Key, t.Format(time.RFC3339Nano)))
+		}
+	}
+
+	if o.durationUnit > 0 && field.Type == zapcore.DurationType {
+		return o.appendNumericDuration(attributes, field)
+	}
+
+	if o.noReflection {
+		return appendZapFieldNoReflect(attributes, field)
+	}
+
+	if o.cache != nil {
+		return o.cache.appendZapField(attributes, field, o.flattenStructs)
+	}
+	if o.flattenStructs {
+		return appendFlattenedField(attributes, field)
+	}
+
+	return appendZapField(attributes, field)
+}
+
 // appendZapField converts and appends a ZAP field.
 func appendZapField(attributes []attribute.KeyValue, field zapcore.Field) []attribute.KeyValue {
 	switch field.Type {
@@ -80,7 +203,7 @@ func appendZapField(attributes []attribute.KeyValue, field zapcore.Field) []attr
 	case zapcore.StringType: // see zap.String()
 		return append(attributes, attribute.String(field.Key, field.String))
 	case zapcore.BinaryType: // see zap.Binary()
-		return append(attributes, attribute.String(field.Key, base64.StdEncoding.EncodeToString(field.Interface.([]byte))))
+		return append(attributes, attribute.String(field.Key, base64String(field.Interface.([]byte))))
 	case zapcore.ByteStringType: // see zap.ByteString()
 		return append(attributes, attribute.String(field.Key, string(field.Interface.([]byte))))
 	case zapcore.StringerType: // see zap.Stringer()
@@ -99,177 +222,465 @@ func appendZapField(attributes []attribute.KeyValue, field zapcore.Field) []attr
 
 	case zapcore.ReflectType, // see zap.Reflect()
 		zapcore.ArrayMarshalerType,  // see zap.Strings(), zap.Int64s(), ...
-		zapcore.ObjectMarshalerType, // see zap.Object()
-		zapcore.InlineMarshalerType: // see zap.Inline()
+		zapcore.ObjectMarshalerType: // see zap.Object()
 		break // return append(attributes, Any(field.Key, field.Interface))
+	case zapcore.InlineMarshalerType: // see zap.Inline()
+		if obj, ok := field.Interface.(zapcore.ObjectMarshaler); ok {
+			return appendInline(attributes, obj, Any)
+		}
+		return attributes // nothing to inline
+
+	default: // unknown, probably a new field type
+		reportUnknownType(field)
+		switch {
+		case field.Interface != nil:
+			break // return append(attributes, Any(field.Key, field.Interface))
+		case field.String != "":
+			return append(attributes, attribute.String(field.Key, field.String))
+		default:
+			return append(attributes, attribute.Int64(field.Key, field.Integer))
+		}
 	}
 
 	return append(attributes, Any(field.Key, field.Interface))
 }
 
-// HTTPHeader converts HTTP headers into OpenTelemetry attribute as multi-line string.
-// The HTTP headers to exclude should be in canonical form (see textproto.CanonicalMIMEHeaderKey).
-func HTTPHeader(key string, header http.Header, exclude map[string]bool) attribute.KeyValue {
-	var buf bytes.Buffer
-	if err := header.WriteSubset(&buf, exclude); err != nil { // unlikely
-		return attribute.String(key, err.Error())
+// unknownTypes are the reported unknown field types.
+var unknownTypes sync.Map // zapcore.FieldType -> struct{}
+
+// reportUnknownType reports the unknown field type, once per type,
+// so the frequent log calls do not flood the error handler.
+func reportUnknownType(field zapcore.Field) {
+	if _, reported := unknownTypes.LoadOrStore(field.Type, struct{}{}); !reported {
+		handleError(fmt.Errorf("otelzap: unknown field type %d of %q", field.Type, field.Key))
 	}
-	return attribute.String(key, buf.String())
+}
+
+// appendInline appends all the fields of the inline marshaler at the top level.
+// The field values are converted by the function (see Any).
+func appendInline(attributes []attribute.KeyValue, obj zapcore.ObjectMarshaler, convert func(string, interface{}) attribute.KeyValue) []attribute.KeyValue {
+	enc := zapcore.NewMapObjectEncoder()
+	if err := obj.MarshalLogObject(enc); err != nil {
+		handleError(fmt.Errorf("otelzap: failed to marshal inline object: %w", err))
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // stable order
+
+	for _, key := range keys {
+		attributes = append(attributes, convert(key, enc.Fields[key]))
+	}
+	return attributes
+}
+
+// appendException appends error as exception semantic convention attributes.
+// The "exception.stacktrace" is added if the error provides
+// the verbose "%+v" form (e.g. github.com/pkg/errors).
+func appendException(attributes []attribute.KeyValue, err error) []attribute.KeyValue {
+	msg := err.Error()
+	attributes = append(attributes,
+		semconv.ExceptionTypeKey.String(reflect.TypeOf(err).String()),
+		semconv.ExceptionMessageKey.String(msg))
+	if f, ok := err.(fmt.Formatter); ok {
+		if verbose := fmt.Sprintf("%+v", f); verbose != msg {
+			attributes = append(attributes, semconv.ExceptionStacktraceKey.String(verbose))
+		}
+	}
+	return attributes
 }
 
 // Any converts unknown type to OpenTelemetry attribute, probably as JSON value.
 func Any(key string, value interface{}) attribute.KeyValue {
+	kv, _ := anyAttribute(key, value)
+	return kv
+}
+
+// anyAttribute converts unknown type to OpenTelemetry attribute
+// and reports the conversion path used (see Explain).
+func anyAttribute(key string, value interface{}) (attribute.KeyValue, ConversionPath) {
+	if kv, path, ok := knownAttribute(key, value); ok {
+		return kv, path
+	}
+
+	// try reflected value
+	if rv := reflect.ValueOf(value); rv.IsValid() {
+		if conv := converterOf(rv.Type()); conv != nil {
+			return conv(key, rv), PathReflection
+		}
+	}
+
+	// format as JSON
+	if b, err := json.Marshal(value); err == nil {
+		return attribute.String(key, string(b)), PathJSON
+	}
+
+	// format as %v string as a final option
+	return attribute.String(key, fmt.Sprint(value)), PathFmt
+}
+
+// knownAttribute converts known types (see the type switch, encoding.TextMarshaler
+// and fmt.Stringer) without reflection. Returns false if type is unknown.
+func knownAttribute(key string, value interface{}) (attribute.KeyValue, ConversionPath, bool) {
 	switch t := value.(type) {
 	case nil:
-		return attribute.String(key, "<nil>")
+		return attribute.String(key, "<nil>"), PathTypeSwitch, true
 
 	case bool:
-		return attribute.Bool(key, t)
+		return attribute.Bool(key, t), PathTypeSwitch, true
 	case []bool:
-		return attribute.BoolSlice(key, t)
+		return attribute.BoolSlice(key, t), PathTypeSwitch, true
 
 	case string:
-		return attribute.String(key, t)
+		return attribute.String(key, t), PathTypeSwitch, true
 	case []string:
-		return attribute.StringSlice(key, t)
+		return attribute.StringSlice(key, t), PathTypeSwitch, true
 	case []byte:
-		return attribute.String(key, base64.StdEncoding.EncodeToString(t))
+		return attribute.String(key, base64String(t)), PathTypeSwitch, true
 
 	case int:
-		return attribute.Int(key, t)
+		return attribute.Int(key, t), PathTypeSwitch, true
 	case []int:
-		return attribute.IntSlice(key, t)
+		return attribute.IntSlice(key, t), PathTypeSwitch, true
+	case [4]int: // fast path for common fixed-size arrays
+		return attribute.IntSlice(key, t[:]), PathTypeSwitch, true
+	case [16]byte: // e.g. UUID
+		return bytesToInt64Slice(key, t[:]), PathTypeSwitch, true
 
 	case int8:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case int16:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case int32:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case int64:
-		return attribute.Int64(key, t)
+		return attribute.Int64(key, t), PathTypeSwitch, true
 	case []int64:
-		return attribute.Int64Slice(key, t)
+		return attribute.Int64Slice(key, t), PathTypeSwitch, true
 
 	case uint:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case uint8:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case uint16:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case uint32:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 	case uint64:
-		return attribute.Int64(key, int64(t))
+		return attribute.Int64(key, int64(t)), PathTypeSwitch, true
 
 	case float32:
-		return attribute.Float64(key, float64(t))
+		return attribute.Float64(key, float64(t)), PathTypeSwitch, true
 	case float64:
-		return attribute.Float64(key, t)
+		return attribute.Float64(key, t), PathTypeSwitch, true
 	case []float64:
-		return attribute.Float64Slice(key, t)
+		return attribute.Float64Slice(key, t), PathTypeSwitch, true
+
+	case trace.SpanContext:
+		return spanContextAttribute(key, t), PathTypeSwitch, true
+	case trace.Link:
+		return linkAttribute(key, t), PathTypeSwitch, true
+	case linkObject: // see Link()
+		return linkAttribute(key, trace.Link(t)), PathTypeSwitch, true
 
 	case encoding.TextMarshaler:
 		if b, err := t.MarshalText(); err == nil {
-			return attribute.String(key, string(b))
+			return attribute.String(key, string(b)), PathTextMarshaler, true
 		}
-		// in case of error just try something else below
+		// in case of error the type is unknown
 	case fmt.Stringer:
-		return attribute.Stringer(key, t)
+		return attribute.Stringer(key, t), PathStringer, true
 	}
 
-	// try reflected value
-	switch rv := reflect.ValueOf(value); rv.Kind() {
+	return attribute.KeyValue{}, "", false // unknown
+}
+
+// bufferPool is a pool of temporary byte buffers.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 256)
+		return &buf
+	},
+}
+
+// base64String encodes binary data as base64 string.
+// The pooled buffer is used, so the only allocation is the resulting string.
+func base64String(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	n := base64.StdEncoding.EncodedLen(len(data))
+	pbuf := bufferPool.Get().(*[]byte)
+	if cap(*pbuf) < n {
+		*pbuf = make([]byte, n)
+	}
+	buf := (*pbuf)[:n]
+	base64.StdEncoding.Encode(buf, data)
+	s := string(buf)
+
+	*pbuf = buf[:0]
+	bufferPool.Put(pbuf)
+	return s
+}
+
+// converter converts reflected value of a specific type to attribute.
+type converter func(key string, rv reflect.Value) attribute.KeyValue
+
+// converters is a cache of converters: reflect.Type -> converter.
+// The nil converter means the type cannot be converted via reflection.
+var converters sync.Map
+
+// converterOf gets the cached converter of the type,
+// so repeated conversion of the app-defined types (e.g. `type UserID int64`)
+// costs a map lookup instead of full reflection.
+func converterOf(t reflect.Type) converter {
+	if conv, ok := converters.Load(t); ok {
+		return conv.(converter)
+	}
+
+	conv := newConverter(t)
+	converters.Store(t, conv)
+	return conv
+}
+
+// newConverter creates a new converter of the type.
+func newConverter(t reflect.Type) converter {
+	switch t.Kind() {
 	case reflect.Bool:
-		return attribute.Bool(key, rv.Bool())
+		return func(key string, rv reflect.Value) attribute.KeyValue {
+			return attribute.Bool(key, rv.Bool())
+		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return attribute.Int64(key, rv.Int())
+		return func(key string, rv reflect.Value) attribute.KeyValue {
+			return attribute.Int64(key, rv.Int())
+		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		return attribute.Int64(key, int64(rv.Uint()))
+		return func(key string, rv reflect.Value) attribute.KeyValue {
+			return attribute.Int64(key, int64(rv.Uint()))
+		}
 	case reflect.Float32, reflect.Float64:
-		return attribute.Float64(key, rv.Float())
+		return func(key string, rv reflect.Value) attribute.KeyValue {
+			return attribute.Float64(key, rv.Float())
+		}
 	case reflect.String:
-		return attribute.String(key, rv.String())
+		return func(key string, rv reflect.Value) attribute.KeyValue {
+			return attribute.String(key, rv.String())
+		}
 
 	case reflect.Slice, reflect.Array:
-		switch rv.Type().Elem().Kind() {
+		switch t.Elem().Kind() {
 		case reflect.Bool:
-			return attribute.BoolSlice(key, toBoolSlice(rv))
+			return func(key string, rv reflect.Value) attribute.KeyValue {
+				return convertPooled(&boolSlices, key, rv, toBoolSlice, attribute.BoolSlice)
+			}
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return attribute.Int64Slice(key, toInt64Slice(rv))
+			return func(key string, rv reflect.Value) attribute.KeyValue {
+				return convertPooled(&int64Slices, key, rv, toInt64Slice, attribute.Int64Slice)
+			}
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-			return attribute.Int64Slice(key, toUint64Slice(rv))
+			return func(key string, rv reflect.Value) attribute.KeyValue {
+				return convertPooled(&int64Slices, key, rv, toUint64Slice, attribute.Int64Slice)
+			}
 		case reflect.Float64:
-			return attribute.Float64Slice(key, toFloat64Slice(rv))
+			return func(key string, rv reflect.Value) attribute.KeyValue {
+				return convertPooled(&float64Slices, key, rv, toFloat64Slice, attribute.Float64Slice)
+			}
 		case reflect.String:
-			return attribute.StringSlice(key, toStringSlice(rv))
+			return func(key string, rv reflect.Value) attribute.KeyValue {
+				return convertPooled(&stringSlices, key, rv, toStringSlice, attribute.StringSlice)
+			}
 		}
 	}
 
-	// format as JSON
-	if b, err := json.Marshal(value); err == nil {
-		return attribute.String(key, string(b))
+	return nil // not supported
+}
+
+// slicePool is a pool of temporary slices.
+// Note, attribute slice values always make their own copy.
+type slicePool[T any] struct {
+	sync.Pool
+}
+
+// Pools of temporary slices used by reflected slice conversions.
+var (
+	boolSlices    slicePool[bool]
+	int64Slices   slicePool[int64]
+	float64Slices slicePool[float64]
+	stringSlices  slicePool[string]
+)
+
+// get gets a temporary slice from the pool.
+func (p *slicePool[T]) get() *[]T {
+	if buf, ok := p.Get().(*[]T); ok {
+		return buf
 	}
+	return new([]T)
+}
 
-	// format as %v string as a final option
-	return attribute.String(key, fmt.Sprint(value))
+// put returns a temporary slice back to the pool.
+func (p *slicePool[T]) put(buf *[]T) {
+	var zero T
+	for i := range *buf {
+		(*buf)[i] = zero // do not hold references
+	}
+	*buf = (*buf)[:0]
+	p.Put(buf)
+}
+
+// convertPooled converts reflected value to attribute using a temporary slice.
+func convertPooled[T any](
+	pool *slicePool[T],
+	key string,
+	rv reflect.Value,
+	to func([]T, reflect.Value) []T,
+	attr func(string, []T) attribute.KeyValue,
+) attribute.KeyValue {
+	buf := pool.get()
+	*buf = to((*buf)[:0], rv)
+	kv := attr(key, *buf)
+	pool.put(buf)
+	return kv
 }
 
 // toBoolSlice converts reflected value to bool slice.
-func toBoolSlice(rv reflect.Value) []bool {
+func toBoolSlice(out []bool, rv reflect.Value) []bool {
 	N := rv.Len()
-	out := make([]bool, N)
 	for i := 0; i < N; i++ {
 		re := rv.Index(i)
-		out[i] = re.Bool()
+		out = append(out, re.Bool())
 	}
 	return out
 }
 
 // toInt64Slice converts reflected value to int64 slice.
-func toInt64Slice(rv reflect.Value) []int64 {
+func toInt64Slice(out []int64, rv reflect.Value) []int64 {
 	N := rv.Len()
-	out := make([]int64, N)
 	for i := 0; i < N; i++ {
 		re := rv.Index(i)
-		out[i] = re.Int()
+		out = append(out, re.Int())
 	}
 	return out
 }
 
 // toUint64Slice converts reflected value to int64 slice.
-func toUint64Slice(rv reflect.Value) []int64 {
+func toUint64Slice(out []int64, rv reflect.Value) []int64 {
 	N := rv.Len()
-	out := make([]int64, N)
 	for i := 0; i < N; i++ {
 		re := rv.Index(i)
-		out[i] = int64(re.Uint())
+		out = append(out, int64(re.Uint()))
 	}
 	return out
 }
 
 // toFloat64Slice converts reflected value to float64 slice.
-func toFloat64Slice(rv reflect.Value) []float64 {
+func toFloat64Slice(out []float64, rv reflect.Value) []float64 {
 	N := rv.Len()
-	out := make([]float64, N)
 	for i := 0; i < N; i++ {
 		re := rv.Index(i)
-		out[i] = re.Float()
+		out = append(out, re.Float())
 	}
 	return out
 }
 
 // toStringSlice converts reflected value to string slice.
-func toStringSlice(rv reflect.Value) []string {
+func toStringSlice(out []string, rv reflect.Value) []string {
 	N := rv.Len()
-	out := make([]string, N)
 	for i := 0; i < N; i++ {
 		re := rv.Index(i)
-		out[i] = re.String()
+		out = append(out, re.String())
 	}
 	return out
 }
 
+// bytesToInt64Slice converts bytes to attribute as int64 slice.
+func bytesToInt64Slice(key string, data []byte) attribute.KeyValue {
+	buf := int64Slices.get()
+	for _, b := range data {
+		*buf = append(*buf, int64(b))
+	}
+	kv := attribute.Int64Slice(key, *buf)
+	int64Slices.put(buf)
+	return kv
+}
+
+// jsonFieldsKey is the attribute key used to pack all the fields as JSON.
+const jsonFieldsKey = "log.fields"
+
+// jsonObject packs multiple attributes into one attribute as JSON object.
+// The duplicate keys are resolved in favor of the last one.
+func jsonObject(key string, attrs []attribute.KeyValue) attribute.KeyValue {
+	b, err := json.Marshal(attributesMap(attrs))
+	if err != nil { // unlikely
+		return attribute.String(key, err.Error())
+	}
+	return attribute.String(key, string(b))
+}
+
+// attributesMap converts attributes to a map, e.g. for JSON encoding.
+func attributesMap(attrs []attribute.KeyValue) map[string]interface{} {
+	obj := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		obj[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	return obj
+}
+
+// excludeOverridden removes the fields overridden by the call-site fields
+// with the same key, so the call-site fields always win.
+func excludeOverridden(with []zapcore.Field, fields []zapcore.Field) []zapcore.Field {
+	if len(with) == 0 || len(fields) == 0 {
+		return with // nothing to override
+	}
+
+	var out []zapcore.Field // allocated on first override
+	for i, w := range with {
+		if hasFieldKey(fields, w.Key) {
+			if out == nil {
+				out = make([]zapcore.Field, 0, len(with)-1)
+				out = append(out, with[:i]...)
+			}
+			continue // overridden
+		}
+		if out != nil {
+			out = append(out, w)
+		}
+	}
+
+	if out == nil {
+		return with // no overrides
+	}
+	return out
+}
+
+// hasFieldKey checks if any of fields has the key.
+// The skipped fields are ignored.
+func hasFieldKey(fields []zapcore.Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key && f.Type != zapcore.SkipType {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixKeys adds the prefix to all attribute keys in place.
+func prefixKeys(attrs []attribute.KeyValue, prefix string) {
+	for i := range attrs {
+		attrs[i].Key = attribute.Key(prefix + string(attrs[i].Key))
+	}
+}
+
+// sortAttributes sorts attributes by key.
+// The order of attributes with the same key is preserved.
+func sortAttributes(attrs []attribute.KeyValue) {
+	sort.SliceStable(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+}
+
 // concatFields concatenates two set of fields.
 func concatFields(a []zapcore.Field, b []zapcore.Field) []zapcore.Field {
 	if len(a) == 0 {
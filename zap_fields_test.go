@@ -1,12 +1,18 @@
 package otelzap
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
 	"net/http"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -93,6 +99,12 @@ func TestAny(t *testing.T) {
 	assert.Equal(t, attribute.Float64Slice("floats", []float64{123.1, 456.2}), Any("floats", []float64{123.1, 456.2}))
 	assert.Equal(t, attribute.Float64Slice("floats", []float64{123.1, 456.2}), Any("floats", Float64s{123.1, 456.2}))
 
+	assert.Equal(t, attribute.Int64Slice("ints", []int64{1, 2, 3, 4}), Any("ints", [4]int{1, 2, 3, 4}))
+	assert.Equal(t, attribute.Int64Slice("bytes", []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}),
+		Any("bytes", [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	assert.Equal(t, attribute.Int64Slice("bytes", []int64{1, 2, 3}), Any("bytes", [3]byte{1, 2, 3})) // via reflection
+	assert.Equal(t, attribute.StringSlice("strs", []string{"c"}), Any("strs", Strings{"c"}))         // reused pool
+
 	assert.Equal(t, attribute.String("stringer", "hello"), Any("stringer", Stringer{"hello"}))
 	assert.Equal(t, attribute.String("array", `["foo","bar",123]`), Any("array", []interface{}{"foo", "bar", 123}))
 	assert.Equal(t, attribute.String("object", `{"foo":"bar"}`), Any("object", map[string]interface{}{"foo": "bar"}))
@@ -153,7 +165,74 @@ func TestAppendZapField(t *testing.T) {
 	assert.Equal(t, []attribute.KeyValue{attribute.String("reflect", `{"Foo":0}`)}, appendZapField(nil, zap.Reflect("reflect", foo)))
 	assert.Equal(t, []attribute.KeyValue{attribute.String("array", `<nil>`)}, appendZapField(nil, zap.Array("array", arr)))
 	assert.Equal(t, []attribute.KeyValue{attribute.String("object", `<nil>`)}, appendZapField(nil, zap.Object("object", obj)))
-	assert.Equal(t, []attribute.KeyValue{attribute.String("", `<nil>`)}, appendZapField(nil, zap.Inline(obj)))
+	assert.Empty(t, appendZapField(nil, zap.Inline(obj)))
+}
+
+// user is used to check zap.Inline expansion.
+type user struct {
+	Name  string
+	Age   int
+	Roles []string
+}
+
+func (u user) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	enc.AddInt("age", u.Age)
+	enc.AddBool("admin", false)
+	return enc.AddReflected("roles", u.Roles)
+}
+
+// TestAppendInline unit tests for zap.Inline expansion.
+func TestAppendInline(t *testing.T) {
+	u := user{Name: "foo", Age: 42, Roles: []string{"a", "b"}}
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Bool("admin", false),
+		attribute.Int("age", 42),
+		attribute.String("name", "foo"),
+		attribute.StringSlice("roles", []string{"a", "b"}),
+	}, appendZapField(nil, zap.Inline(u)))
+}
+
+// TestAppendUnknownZapField unit tests for unknown field types.
+func TestAppendUnknownZapField(t *testing.T) {
+	var errs []error
+	defer func(h func(error)) { handleError = h }(handleError)
+	handleError = func(err error) { errs = append(errs, err) }
+
+	unknown := zapcore.FieldType(250)
+	defer unknownTypes.Delete(unknown)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("int", 123)},
+		appendZapField(nil, zapcore.Field{Key: "int", Type: unknown, Integer: 123}))
+	assert.Equal(t, []attribute.KeyValue{attribute.String("str", "foo")},
+		appendZapField(nil, zapcore.Field{Key: "str", Type: unknown, String: "foo"}))
+	assert.Equal(t, []attribute.KeyValue{attribute.Bool("any", true)},
+		appendZapField(nil, zapcore.Field{Key: "any", Type: unknown, Interface: true}))
+
+	if assert.Len(t, errs, 1) { // reported once
+		assert.EqualError(t, errs[0], `otelzap: unknown field type 250 of "int"`)
+	}
+}
+
+// TestUnknownZapFieldReportedOnce checks the unknown field type
+// is reported once for multiple writes.
+func TestUnknownZapFieldReportedOnce(t *testing.T) {
+	var errs []error
+	defer func(h func(error)) { handleError = h }(handleError)
+	handleError = func(err error) { errs = append(errs, err) }
+
+	unknown := zapcore.FieldType(251)
+	defer unknownTypes.Delete(unknown)
+
+	span := discardSpan{Span: trace.SpanFromContext(context.Background())}
+	core := zapSpanCore{level: zapcore.DebugLevel, span: span, opts: defaultOptions, state: newSpanState()}
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "my message"}
+	field := zapcore.Field{Key: "foo", Type: unknown, Integer: 123}
+	assert.NoError(t, core.Write(entry, []zapcore.Field{field}))
+	assert.NoError(t, core.Write(entry, []zapcore.Field{field}))
+
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], `otelzap: unknown field type 251 of "foo"`)
+	}
 }
 
 // TestAttributes unit tests for attributes.
@@ -212,6 +291,93 @@ func TestAttributes(t *testing.T) {
 			zap.String("bar", "test")))
 }
 
+// TestErrorAsException unit tests for error to exception mapping.
+func TestErrorAsException(t *testing.T) {
+	o := newOptions(WithErrorAsException(false))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("error", assert.AnError.Error()),
+			attribute.String("exception.type", "*errors.errorString"),
+			attribute.String("exception.message", assert.AnError.Error()),
+			attribute.String("other", assert.AnError.Error()),
+		},
+		o.appendZapFields(nil,
+			zap.Error(assert.AnError),
+			zap.NamedError("other", assert.AnError)))
+
+	o = newOptions(WithErrorAsException(true))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("exception.type", "*errors.errorString"),
+			attribute.String("exception.message", assert.AnError.Error()),
+		},
+		o.appendZapFields(nil, zap.Error(assert.AnError)))
+}
+
+// verboseError is an error with the verbose "%+v" form.
+type verboseError struct{}
+
+func (verboseError) Error() string { return "failed" }
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = io.WriteString(s, "failed\nmain.main\n\t/app/main.go:10")
+		return
+	}
+	_, _ = io.WriteString(s, e.Error())
+}
+
+// TestExceptionAttributes unit tests for WithExceptionAttributes option.
+func TestExceptionAttributes(t *testing.T) {
+	o := newOptions(WithExceptionAttributes())
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("exception.type", "*errors.errorString"),
+			attribute.String("exception.message", assert.AnError.Error()),
+			attribute.String("exception.type", "otelzap.verboseError"),
+			attribute.String("exception.message", "failed"),
+			attribute.String("exception.stacktrace", "failed\nmain.main\n\t/app/main.go:10"),
+			attribute.Int("foo", 1),
+		},
+		o.appendZapFields(nil,
+			zap.Error(assert.AnError),
+			zap.NamedError("cause", verboseError{}),
+			zap.Int("foo", 1)))
+}
+
+// TestKeyRenames unit tests for field key renaming.
+func TestKeyRenames(t *testing.T) {
+	o := newOptions(
+		WithKeyRenames(map[string]string{"reqId": "request.id"}),
+		WithKeyRenames(map[string]string{"error": "err"}))
+	assert.Equal(t,
+		[]attribute.KeyValue{
+			attribute.String("request.id", "123"),
+			attribute.Int("foo", 1),
+			attribute.String("err", assert.AnError.Error()),
+		},
+		o.appendZapFields(nil,
+			zap.String("reqId", "123"),
+			zap.Int("foo", 1),
+			zap.Error(assert.AnError)))
+}
+
+// TestEstimateAttrs unit tests for EstimateAttrs function.
+func TestEstimateAttrs(t *testing.T) {
+	assert.Equal(t, 0, EstimateAttrs(nil, nil))
+	assert.Equal(t, 0, EstimateAttrs([]zapcore.Field{zap.Skip()}, []zapcore.Field{zap.Namespace("ns")}))
+	assert.Equal(t, 3, EstimateAttrs(
+		[]zapcore.Field{zap.Int("a", 1), zap.Skip()},
+		[]zapcore.Field{zap.Namespace("ns"), zap.Int("b", 2), zap.String("c", "3")}))
+
+	attrs := attributesFromZapFields(
+		[]zapcore.Field{zap.Int("a", 1), zap.Skip()},
+		[]zapcore.Field{zap.Namespace("ns"), zap.Int("b", 2)},
+		attribute.Int("c", 3))
+	assert.Len(t, attrs, 3)
+	assert.Equal(t, 3, cap(attrs)) // exact
+}
+
 // TestHTTPHeader unit tests for HTTPHeader function.
 func TestHTTPHeader(t *testing.T) {
 	assert.Equal(t,
@@ -238,6 +404,97 @@ func TestHTTPHeader(t *testing.T) {
 		HTTPHeader("foo", h, map[string]bool{"Authorization": true}))
 }
 
+// TestJSONObject unit tests for jsonObject function.
+func TestJSONObject(t *testing.T) {
+	assert.Equal(t, attribute.String("foo", `{}`), jsonObject("foo", nil))
+	assert.Equal(t,
+		attribute.String("foo", `{"a":1,"b":"x","c":[true,false]}`),
+		jsonObject("foo", []attribute.KeyValue{
+			attribute.String("b", "x"),
+			attribute.Int("a", 0),
+			attribute.BoolSlice("c", []bool{true, false}),
+			attribute.Int("a", 1),
+		}))
+}
+
+// TestExcludeOverridden unit tests for excludeOverridden function.
+func TestExcludeOverridden(t *testing.T) {
+	with := []zapcore.Field{zap.Int("a", 1), zap.Int("b", 2), zap.Int("c", 3)}
+	assert.Nil(t, excludeOverridden(nil, with))
+	assert.Equal(t, with, excludeOverridden(with, nil))
+	assert.Equal(t, with, excludeOverridden(with, []zapcore.Field{zap.Int("d", 4), zap.Skip()}))
+	assert.Equal(t,
+		[]zapcore.Field{zap.Int("a", 1), zap.Int("c", 3)},
+		excludeOverridden(with, []zapcore.Field{zap.String("b", "x")}))
+	assert.Empty(t,
+		excludeOverridden(with, []zapcore.Field{zap.Int("c", 0), zap.Int("b", 0), zap.Int("a", 0)}))
+}
+
+// TestBase64String unit tests for base64String function.
+func TestBase64String(t *testing.T) {
+	assert.Equal(t, "", base64String(nil))
+	assert.Equal(t, "AQIDBA==", base64String([]byte{1, 2, 3, 4}))
+
+	big := make([]byte, 1024)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(big), base64String(big))
+	assert.Equal(t, "AQIDBA==", base64String([]byte{1, 2, 3, 4})) // reuse
+}
+
+// BenchmarkAppendZapFieldBinary benchmarks binary field conversion.
+func BenchmarkAppendZapFieldBinary(b *testing.B) {
+	field := zap.Binary("binary", make([]byte, 128))
+	attrs := make([]attribute.KeyValue, 0, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		attrs = appendZapField(attrs[:0], field)
+	}
+}
+
+// BenchmarkAppendZapFieldByteString benchmarks byte string field conversion.
+func BenchmarkAppendZapFieldByteString(b *testing.B) {
+	field := zap.ByteString("byte_string", make([]byte, 128))
+	attrs := make([]attribute.KeyValue, 0, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		attrs = appendZapField(attrs[:0], field)
+	}
+}
+
+// BenchmarkAnyReflectedSlice benchmarks reflected slice conversion.
+func BenchmarkAnyReflectedSlice(b *testing.B) {
+	type Int64s []int64
+	value := Int64s{1, 2, 3, 4, 5, 6, 7, 8}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Any("ints", value)
+	}
+}
+
+// TestConverterOf unit tests for converterOf function.
+func TestConverterOf(t *testing.T) {
+	type UserID int64
+
+	conv := converterOf(reflect.TypeOf(UserID(0)))
+	if assert.NotNil(t, conv) {
+		assert.Equal(t, attribute.Int64("user", 42), conv("user", reflect.ValueOf(UserID(42))))
+	}
+	_, ok := converters.Load(reflect.TypeOf(UserID(0)))
+	assert.True(t, ok) // cached
+
+	assert.Nil(t, converterOf(reflect.TypeOf(struct{}{})))
+	assert.Nil(t, converterOf(reflect.TypeOf(struct{}{}))) // cached nil
+	assert.Nil(t, converterOf(reflect.TypeOf([]struct{}{})))
+}
+
+// BenchmarkAnyNamedType benchmarks named scalar type conversion.
+func BenchmarkAnyNamedType(b *testing.B) {
+	type UserID int64
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Any("user", UserID(i))
+	}
+}
+
 // TestConcat unit tests for concatFields function.
 func TestConcat(t *testing.T) {
 	assert.Nil(t, concatFields(nil, nil))